@@ -2,7 +2,13 @@ package bus
 
 import (
 	"context"
+	"errors"
+	"github.com/Adverax/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type MyBus struct {
@@ -24,4 +30,135 @@ func TestNewChannel(t *testing.T) {
 			return nil
 		},
 	)
+	require.NoError(t, bus.Master.Publish(ctx, true))
+}
+
+func TestChannel_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	ch := NewChannel[int]("test", nil)
+
+	var total int
+	sub := ch.Subscribe(ctx, func(ctx context.Context, notification int) error {
+		total += notification
+		return nil
+	})
+
+	require.NoError(t, ch.Publish(ctx, 1))
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	require.NoError(t, ch.Publish(ctx, 2))
+	assert.Equal(t, 1, total)
+}
+
+func TestChannel_Errors(t *testing.T) {
+	ctx := context.Background()
+	ch := NewChannel[int]("test", nil)
+
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	ch.Subscribe(ctx, func(ctx context.Context, notification int) error { return err1 })
+	ch.Subscribe(ctx, func(ctx context.Context, notification int) error { return nil })
+	ch.Subscribe(ctx, func(ctx context.Context, notification int) error { return err2 })
+
+	err := ch.Publish(ctx, 1)
+	require.Error(t, err)
+	var errs *core.Errors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, 2, errs.Count())
+	assert.True(t, errs.Contains(err1))
+	assert.True(t, errs.Contains(err2))
+}
+
+func TestChannel_Async(t *testing.T) {
+	ctx := context.Background()
+	ch := NewChannel[int]("test", nil, WithAsync(4, 16))
+
+	var total int64
+	for i := 0; i < 3; i++ {
+		ch.Subscribe(ctx, func(ctx context.Context, notification int) error {
+			atomic.AddInt64(&total, int64(notification))
+			return nil
+		})
+	}
+
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, ch.Publish(ctx, i))
+	}
+	ch.Close()
+
+	assert.Equal(t, int64(165), atomic.LoadInt64(&total))
+	assert.ErrorIs(t, ch.Publish(ctx, 1), ErrChannelClosed)
+}
+
+func TestChannel_Serializer(t *testing.T) {
+	ctx := context.Background()
+	var serialized int
+	ch := NewChannel[int]("test", nil, WithSerializer(SerializerFunc(func(notification interface{}) ([]byte, error) {
+		serialized++
+		return nil, nil
+	})))
+
+	require.NoError(t, ch.Publish(ctx, 1))
+	assert.Equal(t, 1, serialized)
+}
+
+func TestChannel_Close(t *testing.T) {
+	ctx := context.Background()
+	ch := NewChannel[int]("test", nil)
+
+	var total int
+	ch.Subscribe(ctx, func(ctx context.Context, notification int) error {
+		total += notification
+		return nil
+	})
+
+	ch.Close()
+	assert.ErrorIs(t, ch.Publish(ctx, 1), ErrChannelClosed)
+	assert.Equal(t, 0, total)
+}
+
+func TestChannel_AsyncSubscribeFromHandler(t *testing.T) {
+	ctx := context.Background()
+	ch := NewChannel[int]("test", nil, WithAsync(1, 1))
+
+	var total int64
+	ch.Subscribe(ctx, func(ctx context.Context, notification int) error {
+		// subscribing while the queue is full must not block the workers
+		sub := ch.Subscribe(ctx, func(ctx context.Context, notification int) error { return nil })
+		sub.Unsubscribe()
+		atomic.AddInt64(&total, int64(notification))
+		return nil
+	})
+
+	for i := 1; i <= 20; i++ {
+		require.NoError(t, ch.Publish(ctx, i))
+	}
+	ch.Close()
+
+	assert.Equal(t, int64(210), atomic.LoadInt64(&total))
+}
+
+func TestChannel_AsyncPublishCanceled(t *testing.T) {
+	ctx := context.Background()
+	ch := NewChannel[int]("test", nil, WithAsync(1, 1))
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	ch.Subscribe(ctx, func(ctx context.Context, notification int) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	require.NoError(t, ch.Publish(ctx, 1))
+	<-started
+	require.NoError(t, ch.Publish(ctx, 2))
+
+	// the worker is busy and the queue is full
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, ch.Publish(timeout, 3), context.DeadlineExceeded)
+
+	close(release)
+	ch.Close()
 }
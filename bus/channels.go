@@ -2,8 +2,9 @@ package bus
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/Adverax/core"
 	"sync"
 )
 
@@ -14,71 +15,262 @@ type Logger interface {
 
 type Subscriber[T any] func(ctx context.Context, notification T) error
 
-type subscribers[T any] []Subscriber[T]
+// Subscription is a handle of the registered subscriber.
+type Subscription interface {
+	Unsubscribe()
+}
+
+type subscribers[T any] []*subscription[T]
 
 type Notifier[T any] interface {
 	Notify(ctx context.Context, notification T)
 }
 
 type Registrar[T any] interface {
-	Subscribe(ctx context.Context, subscriber Subscriber[T])
+	Subscribe(ctx context.Context, subscriber Subscriber[T]) Subscription
 }
 
 type Channel[T any] interface {
-	Publish(ctx context.Context, notification T)
+	Subject() string
+	Publish(ctx context.Context, notification T) error
+	Close()
 	Notifier[T]
 	Registrar[T]
 }
 
-type channel[T any] struct {
+type subscription[T any] struct {
+	channel    *channel[T]
+	subscriber Subscriber[T]
+	once       sync.Once
+}
+
+func (that *subscription[T]) Unsubscribe() {
+	that.once.Do(func() {
+		that.channel.unsubscribe(that)
+	})
+}
+
+type delivery[T any] struct {
+	ctx          context.Context
+	subscription *subscription[T]
+	notification T
+	batch        *batch
+}
+
+// batch collects errors of the single asynchronous publication.
+type batch struct {
 	sync.Mutex
+	wg     sync.WaitGroup
+	errors *core.Errors
+}
+
+func (that *batch) addError(err error) {
+	that.Lock()
+	defer that.Unlock()
+
+	that.errors.AddError(err)
+}
+
+type channel[T any] struct {
+	sync.RWMutex
 	subject     string
 	logger      Logger
+	serializer  Serializer
 	subscribers subscribers[T]
+	queue       chan *delivery[T]
+	workers     sync.WaitGroup
+	sending     sync.WaitGroup
+	closed      bool
+}
+
+func (c *channel[T]) Subject() string {
+	return c.subject
 }
 
-func (c *channel[T]) Subscribe(ctx context.Context, action Subscriber[T]) {
+func (c *channel[T]) Subscribe(ctx context.Context, action Subscriber[T]) Subscription {
 	c.logger.Info(ctx, fmt.Sprintf("NEW SUBSCRIBER: %s", c.subject))
-	c.subscribe(ctx, action)
+	return c.subscribe(ctx, action)
 }
 
-func (c *channel[T]) subscribe(ctx context.Context, action Subscriber[T]) {
+func (c *channel[T]) subscribe(ctx context.Context, action Subscriber[T]) *subscription[T] {
 	c.Lock()
 	defer c.Unlock()
 
-	c.subscribers = append(c.subscribers, action)
+	sub := &subscription[T]{channel: c, subscriber: action}
+	c.subscribers = append(c.subscribers, sub)
+	return sub
+}
+
+func (c *channel[T]) unsubscribe(sub *subscription[T]) {
+	c.Lock()
+	defer c.Unlock()
+
+	for i, s := range c.subscribers {
+		if s == sub {
+			subs := make(subscribers[T], 0, len(c.subscribers)-1)
+			subs = append(subs, c.subscribers[:i]...)
+			c.subscribers = append(subs, c.subscribers[i+1:]...)
+			return
+		}
+	}
 }
 
 func (c *channel[T]) Notify(ctx context.Context, notification T) {
-	c.Publish(ctx, notification)
+	_ = c.Publish(ctx, notification)
 }
 
-func (c *channel[T]) Publish(ctx context.Context, notification T) {
-	data, err := json.Marshal(notification)
+// Publish delivers notification to the subscribers.
+// In the asynchronous mode (see WithAsync) it returns after the notification is queued,
+// so it returns only ErrChannelClosed or error of the context, that is done, while the queue is full.
+// Errors of the subscribers are logged and not returned.
+func (c *channel[T]) Publish(ctx context.Context, notification T) error {
+	data, err := c.serializer.Serialize(notification)
 	if err != nil {
 		c.logger.Error(ctx, err.Error())
 	}
 	c.logger.Info(ctx, fmt.Sprintf("EVENT %s: %s", c.subject, string(data)))
 
-	c.publish(ctx, notification)
+	if c.queue != nil {
+		return c.enqueue(ctx, notification)
+	}
+
+	return c.publish(ctx, notification)
 }
 
-func (c *channel[T]) publish(ctx context.Context, notification T) {
-	c.Lock()
-	defer c.Unlock()
+func (c *channel[T]) publish(ctx context.Context, notification T) error {
+	subs, closed := c.snapshot()
+	if closed {
+		return ErrChannelClosed
+	}
+
+	errs := core.NewErrors()
+	for _, sub := range subs {
+		err := sub.subscriber(ctx, notification)
+		if err != nil {
+			c.logger.Error(ctx, err.Error())
+			errs.AddError(err)
+		}
+	}
+
+	return errs.ResError()
+}
 
-	for _, subscriber := range c.subscribers {
-		err := subscriber(ctx, notification)
+func (c *channel[T]) enqueue(ctx context.Context, notification T) error {
+	// The lock is not held while sending, because handlers may subscribe
+	// or unsubscribe, when the queue is full.
+	c.RLock()
+	if c.closed {
+		c.RUnlock()
+		return ErrChannelClosed
+	}
+	subs := c.subscribers
+	c.sending.Add(1)
+	c.RUnlock()
+	defer c.sending.Done()
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	b := &batch{errors: core.NewErrors()}
+	b.wg.Add(len(subs))
+	for i, sub := range subs {
+		d := &delivery[T]{
+			ctx:          ctx,
+			subscription: sub,
+			notification: notification,
+			batch:        b,
+		}
+		select {
+		case c.queue <- d:
+		case <-ctx.Done():
+			// the rest of the subscribers don't receive the notification
+			b.wg.Add(i - len(subs))
+			return ctx.Err()
+		}
+	}
+
+	go func() {
+		b.wg.Wait()
+		if err := b.errors.ResError(); err != nil {
+			c.logger.Error(ctx, fmt.Sprintf("EVENT %s FAILED: %s", c.subject, err.Error()))
+		}
+	}()
+
+	return nil
+}
+
+func (c *channel[T]) snapshot() (subscribers[T], bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.subscribers, c.closed
+}
+
+func (c *channel[T]) work() {
+	defer c.workers.Done()
+
+	for d := range c.queue {
+		err := d.subscription.subscriber(d.ctx, d.notification)
 		if err != nil {
-			c.logger.Info(ctx, err.Error())
+			d.batch.addError(err)
 		}
+		d.batch.wg.Done()
 	}
 }
 
-func NewChannel[T any](subject string, logger Logger) Channel[T] {
-	return &channel[T]{
-		subject:     subject,
-		logger:      logger,
-		subscribers: make(subscribers[T], 4),
+// Close stops accepting notifications and waits
+// until all queued ones are delivered.
+func (c *channel[T]) Close() {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		return
+	}
+	c.closed = true
+	c.Unlock()
+
+	if c.queue != nil {
+		c.sending.Wait()
+		close(c.queue)
+		c.workers.Wait()
 	}
 }
+
+func NewChannel[T any](subject string, logger Logger, options ...Option) Channel[T] {
+	opts := &Options{
+		serializer: JSONSerializer,
+	}
+	for _, option := range options {
+		option(opts)
+	}
+
+	if logger == nil {
+		logger = dummyLogger{}
+	}
+
+	c := &channel[T]{
+		subject:    subject,
+		logger:     logger,
+		serializer: opts.serializer,
+	}
+
+	if opts.workers > 0 {
+		c.queue = make(chan *delivery[T], opts.queueSize)
+		c.workers.Add(opts.workers)
+		for i := 0; i < opts.workers; i++ {
+			go c.work()
+		}
+	}
+
+	return c
+}
+
+type dummyLogger struct{}
+
+func (dummyLogger) Error(ctx context.Context, msg string) {}
+func (dummyLogger) Info(ctx context.Context, msg string)  {}
+
+var (
+	ErrChannelClosed = errors.New("channel is closed")
+)
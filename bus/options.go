@@ -0,0 +1,43 @@
+package bus
+
+import (
+	"encoding/json"
+)
+
+// Serializer converts notification into the text representation used for logging.
+type Serializer interface {
+	Serialize(notification interface{}) ([]byte, error)
+}
+
+type SerializerFunc func(notification interface{}) ([]byte, error)
+
+func (fn SerializerFunc) Serialize(notification interface{}) ([]byte, error) {
+	return fn(notification)
+}
+
+var JSONSerializer Serializer = SerializerFunc(json.Marshal)
+
+type Options struct {
+	workers    int
+	queueSize  int
+	serializer Serializer
+}
+
+type Option func(*Options)
+
+// WithAsync enables asynchronous delivery through the pool of workers.
+// Publish doesn't wait for the subscribers, their errors are only logged.
+func WithAsync(workers, queueSize int) Option {
+	return func(options *Options) {
+		options.workers = workers
+		options.queueSize = queueSize
+	}
+}
+
+func WithSerializer(serializer Serializer) Option {
+	return func(options *Options) {
+		if serializer != nil {
+			options.serializer = serializer
+		}
+	}
+}
@@ -62,6 +62,11 @@ func (that *Errors) AddErrors(errs *Errors) {
 	that.errors = append(that.errors, errs.errors...)
 }
 
+// Unwrap returns the errors, so the errors can be inspected one by one.
+func (that *Errors) Unwrap() []error {
+	return append([]error(nil), that.errors...)
+}
+
 // IsEmpty returns true if there are no errors present.
 func (that *Errors) IsEmpty() bool {
 	return len(that.errors) == 0
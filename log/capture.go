@@ -0,0 +1,356 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"github.com/Adverax/core/log/logrus"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+)
+
+// CapturedEntry is the entry recorded by CaptureLogger.
+type CapturedEntry struct {
+	Level   Level
+	Message string
+	Fields  Fields
+	// Error is the error attached with WithError or Err field.
+	Error error
+}
+
+func (that CapturedEntry) String() string {
+	return fmt.Sprintf("%s: %s %v", that.Level, that.Message, that.Fields)
+}
+
+// capture is the storage of the entries, that is shared by the derived loggers.
+type capture struct {
+	mx      sync.Mutex
+	level   Level
+	entries []CapturedEntry
+}
+
+// CaptureLogger is the logger for tests, that records the entries in memory.
+// Loggers derived with WithField, WithFields and WithError record into the same storage.
+// Fatal entries are recorded without exit, Panic entries are recorded before panic.
+type CaptureLogger struct {
+	capture *capture
+	fields  Fields
+}
+
+// NewCaptureLogger makes logger, that records entries of all levels.
+func NewCaptureLogger() *CaptureLogger {
+	return &CaptureLogger{
+		capture: &capture{level: TraceLevel},
+	}
+}
+
+// SetLevel sets the least severe level of the recorded entries.
+func (that *CaptureLogger) SetLevel(level Level) {
+	that.capture.mx.Lock()
+	defer that.capture.mx.Unlock()
+
+	that.capture.level = level
+}
+
+// Entries returns recorded entries in order of the logging.
+func (that *CaptureLogger) Entries() []CapturedEntry {
+	that.capture.mx.Lock()
+	defer that.capture.mx.Unlock()
+
+	return append([]CapturedEntry(nil), that.capture.entries...)
+}
+
+// Filter returns recorded entries of the level.
+func (that *CaptureLogger) Filter(level Level) []CapturedEntry {
+	return that.FilterFunc(func(entry CapturedEntry) bool {
+		return entry.Level == level
+	})
+}
+
+// FilterFunc returns recorded entries accepted by the filter.
+func (that *CaptureLogger) FilterFunc(filter func(entry CapturedEntry) bool) []CapturedEntry {
+	var res []CapturedEntry
+	for _, entry := range that.Entries() {
+		if filter(entry) {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
+
+// ContainsMessage returns true if any recorded message contains the text.
+func (that *CaptureLogger) ContainsMessage(text string) bool {
+	return len(that.messages(nil, text)) != 0
+}
+
+// Reset removes all recorded entries.
+func (that *CaptureLogger) Reset() {
+	that.capture.mx.Lock()
+	defer that.capture.mx.Unlock()
+
+	that.capture.entries = nil
+}
+
+// AssertLogged asserts that the entry of the level with message containing the text is recorded.
+func (that *CaptureLogger) AssertLogged(t assert.TestingT, level Level, text string) bool {
+	if len(that.messages(&level, text)) != 0 {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("Entry %q of level %s is not logged", text, level), that.dump())
+}
+
+// AssertNotLogged asserts that no entry of the level with message containing the text is recorded.
+func (that *CaptureLogger) AssertNotLogged(t assert.TestingT, level Level, text string) bool {
+	if len(that.messages(&level, text)) == 0 {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("Entry %q of level %s is logged", text, level), that.dump())
+}
+
+// AssertCount asserts the count of the recorded entries of the level.
+func (that *CaptureLogger) AssertCount(t assert.TestingT, level Level, count int) bool {
+	return assert.Len(t, that.Filter(level), count, that.dump())
+}
+
+// AssertField asserts that the entry with message containing the text has the field with the value.
+func (that *CaptureLogger) AssertField(t assert.TestingT, text string, key string, value interface{}) bool {
+	entries := that.messages(nil, text)
+	if len(entries) == 0 {
+		return assert.Fail(t, fmt.Sprintf("Entry %q is not logged", text), that.dump())
+	}
+
+	for _, entry := range entries {
+		if v, ok := entry.Fields[key]; ok && assert.ObjectsAreEqual(value, v) {
+			return true
+		}
+	}
+
+	return assert.Fail(t, fmt.Sprintf("Entry %q has no field %s=%v", text, key, value), that.dump())
+}
+
+func (that *CaptureLogger) messages(level *Level, text string) []CapturedEntry {
+	return that.FilterFunc(func(entry CapturedEntry) bool {
+		return (level == nil || entry.Level == *level) && strings.Contains(entry.Message, text)
+	})
+}
+
+// dump returns recorded entries for the messages of the failed assertions.
+func (that *CaptureLogger) dump() string {
+	entries := that.Entries()
+	if len(entries) == 0 {
+		return "No entries are logged"
+	}
+
+	var b strings.Builder
+	b.WriteString("Logged entries:")
+	for _, entry := range entries {
+		b.WriteString("\n\t")
+		b.WriteString(entry.String())
+	}
+	return b.String()
+}
+
+func (that *CaptureLogger) with(fields Fields) *CaptureLogger {
+	fs := make(Fields, len(that.fields)+len(fields))
+	for k, v := range that.fields {
+		fs[k] = v
+	}
+	for k, v := range fields {
+		fs[k] = v
+	}
+
+	return &CaptureLogger{capture: that.capture, fields: fs}
+}
+
+func (that *CaptureLogger) log(level Level, msg string, fields Fields) {
+	if !that.Enabled(context.Background(), level) {
+		return
+	}
+
+	if fields == nil {
+		fields = that.with(nil).fields
+	}
+	entry := CapturedEntry{
+		Level:   level,
+		Message: msg,
+		Fields:  fields,
+	}
+	if err, ok := fields[logrus.ErrorKey].(error); ok {
+		entry.Error = err
+	}
+
+	that.capture.mx.Lock()
+	that.capture.entries = append(that.capture.entries, entry)
+	that.capture.mx.Unlock()
+
+	if level == PanicLevel {
+		panic(msg)
+	}
+}
+
+func (that *CaptureLogger) logf(level Level, format string, args []interface{}) {
+	that.log(level, fmt.Sprintf(format, args...), nil)
+}
+
+func (that *CaptureLogger) logs(level Level, args []interface{}) {
+	that.log(level, fmt.Sprint(args...), nil)
+}
+
+func (that *CaptureLogger) logln(level Level, args []interface{}) {
+	msg := fmt.Sprintln(args...)
+	that.log(level, msg[:len(msg)-1], nil)
+}
+
+func (that *CaptureLogger) logw(level Level, msg string, fields []Field) {
+	fs := make(Fields, len(fields))
+	for _, f := range fields {
+		if f.Type != ErrorType {
+			fs[f.Key] = f.Value()
+			continue
+		}
+
+		if err, ok := f.Interface.(error); ok && err != nil {
+			fs[f.Key] = err
+		}
+	}
+
+	that.log(level, msg, that.with(fs).fields)
+}
+
+// NewContext returns context with the logger, so Resolve returns the logger with its fields.
+func (that *CaptureLogger) NewContext(ctx context.Context) context.Context {
+	return NewContext(ctx, that)
+}
+
+func (that *CaptureLogger) WithField(ctx context.Context, key string, value interface{}) Logger {
+	return that.with(Fields{key: value})
+}
+
+func (that *CaptureLogger) WithFields(ctx context.Context, fields Fields) Logger {
+	return that.with(fields)
+}
+
+func (that *CaptureLogger) WithError(ctx context.Context, err error) Logger {
+	return that.with(Fields{logrus.ErrorKey: err})
+}
+
+func (that *CaptureLogger) Enabled(ctx context.Context, level Level) bool {
+	that.capture.mx.Lock()
+	defer that.capture.mx.Unlock()
+
+	return level <= that.capture.level
+}
+
+func (that *CaptureLogger) Tracef(ctx context.Context, format string, args ...interface{}) {
+	that.logf(TraceLevel, format, args)
+}
+
+func (that *CaptureLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(DebugLevel, format, args)
+}
+
+func (that *CaptureLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	that.logf(InfoLevel, format, args)
+}
+
+func (that *CaptureLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(WarnLevel, format, args)
+}
+
+func (that *CaptureLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ErrorLevel, format, args)
+}
+
+func (that *CaptureLogger) Fatalf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(FatalLevel, format, args)
+}
+
+func (that *CaptureLogger) Panicf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(PanicLevel, format, args)
+}
+
+func (that *CaptureLogger) Trace(ctx context.Context, args ...interface{}) {
+	that.logs(TraceLevel, args)
+}
+
+func (that *CaptureLogger) Debug(ctx context.Context, args ...interface{}) {
+	that.logs(DebugLevel, args)
+}
+
+func (that *CaptureLogger) Info(ctx context.Context, args ...interface{}) {
+	that.logs(InfoLevel, args)
+}
+
+func (that *CaptureLogger) Warning(ctx context.Context, args ...interface{}) {
+	that.logs(WarnLevel, args)
+}
+
+func (that *CaptureLogger) Error(ctx context.Context, args ...interface{}) {
+	that.logs(ErrorLevel, args)
+}
+
+func (that *CaptureLogger) Fatal(ctx context.Context, args ...interface{}) {
+	that.logs(FatalLevel, args)
+}
+
+func (that *CaptureLogger) Panic(ctx context.Context, args ...interface{}) {
+	that.logs(PanicLevel, args)
+}
+
+func (that *CaptureLogger) Traceln(ctx context.Context, args ...interface{}) {
+	that.logln(TraceLevel, args)
+}
+
+func (that *CaptureLogger) Debugln(ctx context.Context, args ...interface{}) {
+	that.logln(DebugLevel, args)
+}
+
+func (that *CaptureLogger) Infoln(ctx context.Context, args ...interface{}) {
+	that.logln(InfoLevel, args)
+}
+
+func (that *CaptureLogger) Warningln(ctx context.Context, args ...interface{}) {
+	that.logln(WarnLevel, args)
+}
+
+func (that *CaptureLogger) Errorln(ctx context.Context, args ...interface{}) {
+	that.logln(ErrorLevel, args)
+}
+
+func (that *CaptureLogger) Fatalln(ctx context.Context, args ...interface{}) {
+	that.logln(FatalLevel, args)
+}
+
+func (that *CaptureLogger) Panicln(ctx context.Context, args ...interface{}) {
+	that.logln(PanicLevel, args)
+}
+
+func (that *CaptureLogger) Tracew(ctx context.Context, msg string, fields ...Field) {
+	that.logw(TraceLevel, msg, fields)
+}
+
+func (that *CaptureLogger) Debugw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(DebugLevel, msg, fields)
+}
+
+func (that *CaptureLogger) Infow(ctx context.Context, msg string, fields ...Field) {
+	that.logw(InfoLevel, msg, fields)
+}
+
+func (that *CaptureLogger) Warningw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(WarnLevel, msg, fields)
+}
+
+func (that *CaptureLogger) Errorw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ErrorLevel, msg, fields)
+}
+
+func (that *CaptureLogger) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(FatalLevel, msg, fields)
+}
+
+func (that *CaptureLogger) Panicw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(PanicLevel, msg, fields)
+}
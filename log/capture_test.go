@@ -0,0 +1,102 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type failureRecorder struct {
+	failures []string
+}
+
+func (that *failureRecorder) Errorf(format string, args ...interface{}) {
+	that.failures = append(that.failures, format)
+}
+
+func TestCaptureLogger(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	capture := NewCaptureLogger()
+	logger := capture.WithField(ctx, "user", 1).WithFields(ctx, Fields{"order": "A1"})
+
+	logger.Infof(ctx, "order %s is created", "A1")
+	logger.WithError(ctx, errFailed).Error(ctx, "order is not paid")
+	capture.Warningln(ctx, "slow", "request")
+	capture.Debugw(ctx, "query", Str("table", "orders"), Err(errFailed))
+
+	entries := capture.Entries()
+	require.Len(t, entries, 4)
+	assert.Equal(t, CapturedEntry{
+		Level:   InfoLevel,
+		Message: "order A1 is created",
+		Fields:  Fields{"user": 1, "order": "A1"},
+	}, entries[0])
+	assert.Equal(t, errFailed, entries[1].Error)
+	assert.Equal(t, Fields{"user": 1, "order": "A1", "error": errFailed}, entries[1].Fields)
+	assert.Equal(t, "slow request", entries[2].Message)
+	assert.Equal(t, Fields{"table": "orders", "error": errFailed}, entries[3].Fields)
+	assert.Equal(t, errFailed, entries[3].Error)
+
+	assert.Len(t, capture.Filter(ErrorLevel), 1)
+	assert.True(t, capture.ContainsMessage("is created"))
+	assert.False(t, capture.ContainsMessage("is deleted"))
+
+	capture.AssertLogged(t, InfoLevel, "is created")
+	capture.AssertNotLogged(t, ErrorLevel, "is created")
+	capture.AssertCount(t, WarnLevel, 1)
+	capture.AssertField(t, "is created", "order", "A1")
+
+	capture.Reset()
+	assert.Empty(t, capture.Entries())
+}
+
+func TestCaptureLogger_FailedAssertions(t *testing.T) {
+	ctx := context.Background()
+	capture := NewCaptureLogger()
+	capture.Info(ctx, "started")
+
+	rec := &failureRecorder{}
+	assert.False(t, capture.AssertLogged(rec, ErrorLevel, "started"))
+	assert.False(t, capture.AssertNotLogged(rec, InfoLevel, "started"))
+	assert.False(t, capture.AssertCount(rec, InfoLevel, 2))
+	assert.False(t, capture.AssertField(rec, "started", "user", 1))
+	assert.Len(t, rec.failures, 4)
+}
+
+func TestCaptureLogger_Level(t *testing.T) {
+	ctx := context.Background()
+	capture := NewCaptureLogger()
+	capture.SetLevel(WarnLevel)
+
+	capture.Debug(ctx, "skipped")
+	capture.Fatal(ctx, "recorded without exit")
+
+	assert.False(t, capture.Enabled(ctx, InfoLevel))
+	assert.False(t, capture.ContainsMessage("skipped"))
+	capture.AssertLogged(t, FatalLevel, "without exit")
+
+	assert.PanicsWithValue(t, "boom", func() {
+		capture.Panicf(ctx, "%s", "boom")
+	})
+	capture.AssertLogged(t, PanicLevel, "boom")
+}
+
+func TestCaptureLogger_Context(t *testing.T) {
+	capture := NewCaptureLogger()
+
+	ctx := capture.WithField(context.Background(), "request", "r1").NewContext(context.Background())
+	Resolve(ctx).Info(ctx, "resolved")
+	Infof(ctx, "package %s", "level")
+
+	logger := NewContextLogger(capture, ContextModeOpaque)
+	ctx = logger.NewContext(context.Background())
+	logger.Warning(ctx, "through context")
+
+	capture.AssertField(t, "resolved", "request", "r1")
+	capture.AssertField(t, "package level", "request", "r1")
+	capture.AssertLogged(t, WarnLevel, "through context")
+}
@@ -76,13 +76,19 @@ func NewContextLogger(logger Logger, mode ContextMode) Logger {
 	return NewCustomLogger(logger, behavior)
 }
 
-type String interface {
+// StringProvider provides string option of the logger.
+type StringProvider interface {
 	Get(ctx context.Context) (string, error)
 }
 
+// String is the former name of StringProvider.
+//
+// Deprecated: use StringProvider.
+type String = StringProvider
+
 type ContextLoggerOptions struct {
-	Level   String
-	Context String
+	Level   StringProvider
+	Context StringProvider
 }
 
 type LevelBuilder interface {
@@ -2,6 +2,7 @@ package log
 
 import (
 	"context"
+	"github.com/Adverax/core/trace"
 )
 
 type Behavior interface {
@@ -28,100 +29,159 @@ func (that *CustomLogger) NewContext(ctx context.Context) context.Context {
 	return that.behavior.NewContext(ctx)
 }
 
+// resolve returns logger of the context, that adds trace context to the entries.
+func (that *CustomLogger) resolve(ctx context.Context) Logger {
+	return withTrace(ctx, that.behavior.Resolve(ctx))
+}
+
+// entry returns logger of the context for the entry of the level.
+// Trace context is added only to the enabled entries, so the disabled ones don't allocate.
+func (that *CustomLogger) entry(ctx context.Context, level Level) Logger {
+	logger := that.behavior.Resolve(ctx)
+	if !IsEnabled(ctx, logger, level) {
+		return logger
+	}
+
+	return withTrace(ctx, logger)
+}
+
+func withTrace(ctx context.Context, logger Logger) Logger {
+	if sc, ok := trace.FromContext(ctx); ok {
+		return logger.WithFields(ctx, Fields{
+			FieldKeyTraceID: sc.TraceID.String(),
+			FieldKeySpanID:  sc.SpanID.String(),
+		})
+	}
+
+	return logger
+}
+
 func (that *CustomLogger) WithField(ctx context.Context, key string, value interface{}) Logger {
-	return that.behavior.Resolve(ctx).WithField(ctx, key, value)
+	return that.resolve(ctx).WithField(ctx, key, value)
 }
 
 func (that *CustomLogger) WithFields(ctx context.Context, fields Fields) Logger {
-	return that.behavior.Resolve(ctx).WithFields(ctx, fields)
+	return that.resolve(ctx).WithFields(ctx, fields)
 }
 
 func (that *CustomLogger) WithError(ctx context.Context, err error) Logger {
-	return that.behavior.Resolve(ctx).WithError(ctx, err)
+	return that.resolve(ctx).WithError(ctx, err)
 }
 
 func (that *CustomLogger) Tracef(ctx context.Context, format string, args ...interface{}) {
-	that.behavior.Resolve(ctx).Tracef(ctx, format, args...)
+	that.entry(ctx, TraceLevel).Tracef(ctx, format, args...)
 }
 
 func (that *CustomLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
-	that.behavior.Resolve(ctx).Debugf(ctx, format, args...)
+	that.entry(ctx, DebugLevel).Debugf(ctx, format, args...)
 }
 
 func (that *CustomLogger) Infof(ctx context.Context, format string, args ...interface{}) {
-	that.behavior.Resolve(ctx).Infof(ctx, format, args...)
+	that.entry(ctx, InfoLevel).Infof(ctx, format, args...)
 }
 
 func (that *CustomLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
-	that.behavior.Resolve(ctx).Warningf(ctx, format, args...)
+	that.entry(ctx, WarnLevel).Warningf(ctx, format, args...)
 }
 
 func (that *CustomLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
-	that.behavior.Resolve(ctx).Errorf(ctx, format, args...)
+	that.entry(ctx, ErrorLevel).Errorf(ctx, format, args...)
 }
 
 func (that *CustomLogger) Fatalf(ctx context.Context, format string, args ...interface{}) {
-	that.behavior.Resolve(ctx).Fatalf(ctx, format, args...)
+	that.entry(ctx, FatalLevel).Fatalf(ctx, format, args...)
 }
 
 func (that *CustomLogger) Panicf(ctx context.Context, format string, args ...interface{}) {
-	that.behavior.Resolve(ctx).Panicf(ctx, format, args...)
+	that.entry(ctx, PanicLevel).Panicf(ctx, format, args...)
 }
 
 func (that *CustomLogger) Trace(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Trace(ctx, args...)
+	that.entry(ctx, TraceLevel).Trace(ctx, args...)
 }
 
 func (that *CustomLogger) Debug(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Debug(ctx, args...)
+	that.entry(ctx, DebugLevel).Debug(ctx, args...)
 }
 
 func (that *CustomLogger) Info(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Info(ctx, args...)
+	that.entry(ctx, InfoLevel).Info(ctx, args...)
 }
 
 func (that *CustomLogger) Warning(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Warning(ctx, args...)
+	that.entry(ctx, WarnLevel).Warning(ctx, args...)
 }
 
 func (that *CustomLogger) Error(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Error(ctx, args...)
+	that.entry(ctx, ErrorLevel).Error(ctx, args...)
 }
 
 func (that *CustomLogger) Fatal(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Fatal(ctx, args...)
+	that.entry(ctx, FatalLevel).Fatal(ctx, args...)
 }
 
 func (that *CustomLogger) Panic(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Panic(ctx, args...)
+	that.entry(ctx, PanicLevel).Panic(ctx, args...)
 }
 
 func (that *CustomLogger) Traceln(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Traceln(ctx, args...)
+	that.entry(ctx, TraceLevel).Traceln(ctx, args...)
 }
 
 func (that *CustomLogger) Debugln(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Debugln(ctx, args...)
+	that.entry(ctx, DebugLevel).Debugln(ctx, args...)
 }
 
 func (that *CustomLogger) Infoln(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Infoln(ctx, args...)
+	that.entry(ctx, InfoLevel).Infoln(ctx, args...)
 }
 
 func (that *CustomLogger) Warningln(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Warningln(ctx, args...)
+	that.entry(ctx, WarnLevel).Warningln(ctx, args...)
 }
 
 func (that *CustomLogger) Errorln(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Errorln(ctx, args...)
+	that.entry(ctx, ErrorLevel).Errorln(ctx, args...)
 }
 
 func (that *CustomLogger) Fatalln(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Fatalln(ctx, args...)
+	that.entry(ctx, FatalLevel).Fatalln(ctx, args...)
 }
 
 func (that *CustomLogger) Panicln(ctx context.Context, args ...interface{}) {
-	that.behavior.Resolve(ctx).Panicln(ctx, args...)
+	that.entry(ctx, PanicLevel).Panicln(ctx, args...)
+}
+
+func (that *CustomLogger) Enabled(ctx context.Context, level Level) bool {
+	return IsEnabled(ctx, that.behavior.Resolve(ctx), level)
+}
+
+func (that *CustomLogger) Tracew(ctx context.Context, msg string, fields ...Field) {
+	Logw(ctx, that.entry(ctx, TraceLevel), TraceLevel, msg, fields...)
+}
+
+func (that *CustomLogger) Debugw(ctx context.Context, msg string, fields ...Field) {
+	Logw(ctx, that.entry(ctx, DebugLevel), DebugLevel, msg, fields...)
+}
+
+func (that *CustomLogger) Infow(ctx context.Context, msg string, fields ...Field) {
+	Logw(ctx, that.entry(ctx, InfoLevel), InfoLevel, msg, fields...)
+}
+
+func (that *CustomLogger) Warningw(ctx context.Context, msg string, fields ...Field) {
+	Logw(ctx, that.entry(ctx, WarnLevel), WarnLevel, msg, fields...)
+}
+
+func (that *CustomLogger) Errorw(ctx context.Context, msg string, fields ...Field) {
+	Logw(ctx, that.entry(ctx, ErrorLevel), ErrorLevel, msg, fields...)
+}
+
+func (that *CustomLogger) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	Logw(ctx, that.entry(ctx, FatalLevel), FatalLevel, msg, fields...)
+}
+
+func (that *CustomLogger) Panicw(ctx context.Context, msg string, fields ...Field) {
+	Logw(ctx, that.entry(ctx, PanicLevel), PanicLevel, msg, fields...)
 }
 
 type FieldsBehavior struct {
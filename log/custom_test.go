@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/Adverax/core/trace"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCustomLogger_TraceContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewContextLogger(newFieldsLogger(t, buf, InfoLevel), ContextModeNone)
+	sc := trace.New()
+	ctx := trace.NewContext(context.Background(), sc)
+
+	l.WithField(ctx, "id", 1).Info(ctx, "message")
+
+	assert.JSONEq(t, `{
+		"level": "info",
+		"msg": "message",
+		"id": 1,
+		"trace_id": "`+sc.TraceID.String()+`",
+		"span_id": "`+sc.SpanID.String()+`"
+	}`, buf.String())
+}
+
+func TestCustomLogger_TraceContextDisabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewContextLogger(newFieldsLogger(t, buf, InfoLevel), ContextModeNone)
+	ctx := trace.NewContext(context.Background(), trace.New())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Logw(ctx, l, DebugLevel, "message")
+	})
+	assert.Zero(t, allocs)
+	assert.Empty(t, buf.String())
+
+	l.Infof(ctx, "message %d", 1)
+	assert.Contains(t, buf.String(), `"trace_id"`)
+}
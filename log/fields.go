@@ -0,0 +1,245 @@
+package log
+
+import (
+	"context"
+	"github.com/Adverax/core/log/logrus"
+	"math"
+	"time"
+)
+
+type FieldType uint8
+
+const (
+	UnknownType FieldType = iota
+	StringType
+	Int64Type
+	BoolType
+	Float64Type
+	DurationType
+	ErrorType
+	AnyType
+)
+
+// Field is a typed field of the log entry.
+// Typed fields don't box the values, so they don't allocate until the entry is written.
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	String    string
+	Interface interface{}
+}
+
+// Value returns value of the field.
+func (that Field) Value() interface{} {
+	switch that.Type {
+	case StringType:
+		return that.String
+	case Int64Type:
+		return that.Integer
+	case BoolType:
+		return that.Integer == 1
+	case Float64Type:
+		return math.Float64frombits(uint64(that.Integer))
+	case DurationType:
+		return time.Duration(that.Integer)
+	default:
+		return that.Interface
+	}
+}
+
+// Str creates the string field. It is not named String, because String is the deprecated alias of StringProvider.
+func Str(key string, val string) Field {
+	return Field{Key: key, Type: StringType, String: val}
+}
+
+func Int(key string, val int) Field {
+	return Field{Key: key, Type: Int64Type, Integer: int64(val)}
+}
+
+func Int64(key string, val int64) Field {
+	return Field{Key: key, Type: Int64Type, Integer: val}
+}
+
+func Bool(key string, val bool) Field {
+	var i int64
+	if val {
+		i = 1
+	}
+	return Field{Key: key, Type: BoolType, Integer: i}
+}
+
+func Float64(key string, val float64) Field {
+	return Field{Key: key, Type: Float64Type, Integer: int64(math.Float64bits(val))}
+}
+
+func Duration(key string, val time.Duration) Field {
+	return Field{Key: key, Type: DurationType, Integer: int64(val)}
+}
+
+// Err makes field with the error under the standard error key. Nil error is omitted.
+func Err(err error) Field {
+	return Field{Key: logrus.ErrorKey, Type: ErrorType, Interface: err}
+}
+
+func Any(key string, val interface{}) Field {
+	return Field{Key: key, Type: AnyType, Interface: val}
+}
+
+// makeFields converts typed fields into the fields of the logrus entry.
+func makeFields(data logrus.Fields, errors ErrorTuner, fields []Field) logrus.Fields {
+	fs := make(logrus.Fields, len(data)+len(fields))
+	for k, v := range data {
+		fs[k] = v
+	}
+
+	for _, f := range fields {
+		if f.Type != ErrorType {
+			fs[f.Key] = f.Value()
+			continue
+		}
+
+		if err, ok := f.Interface.(error); ok && err != nil {
+			fs[f.Key] = errors.TuneError(err)
+		}
+	}
+
+	return fs
+}
+
+// IsEnabled returns true if the logger writes entries of the level.
+// Loggers, that don't implement FieldsLogger, are considered enabled for all levels.
+func IsEnabled(ctx context.Context, logger Logger, level Level) bool {
+	if l, ok := logger.(FieldsLogger); ok {
+		return l.Enabled(ctx, level)
+	}
+	return true
+}
+
+// Logw writes entry with typed fields to any logger.
+// Loggers, that don't implement FieldsLogger, receive the fields as the untyped ones.
+func Logw(ctx context.Context, logger Logger, level Level, msg string, fields ...Field) {
+	if l, ok := logger.(FieldsLogger); ok {
+		switch level {
+		case TraceLevel:
+			l.Tracew(ctx, msg, fields...)
+		case DebugLevel:
+			l.Debugw(ctx, msg, fields...)
+		case InfoLevel:
+			l.Infow(ctx, msg, fields...)
+		case WarnLevel:
+			l.Warningw(ctx, msg, fields...)
+		case ErrorLevel:
+			l.Errorw(ctx, msg, fields...)
+		case FatalLevel:
+			l.Fatalw(ctx, msg, fields...)
+		case PanicLevel:
+			l.Panicw(ctx, msg, fields...)
+		}
+		return
+	}
+
+	if len(fields) != 0 {
+		fs := make(Fields, len(fields))
+		for _, f := range fields {
+			if f.Type == ErrorType && f.Interface == nil {
+				continue
+			}
+			fs[f.Key] = f.Value()
+		}
+		logger = logger.WithFields(ctx, fs)
+	}
+
+	switch level {
+	case TraceLevel:
+		logger.Trace(ctx, msg)
+	case DebugLevel:
+		logger.Debug(ctx, msg)
+	case InfoLevel:
+		logger.Info(ctx, msg)
+	case WarnLevel:
+		logger.Warning(ctx, msg)
+	case ErrorLevel:
+		logger.Error(ctx, msg)
+	case FatalLevel:
+		logger.Fatal(ctx, msg)
+	case PanicLevel:
+		logger.Panic(ctx, msg)
+	}
+}
+
+// logw writes entry with typed fields.
+func logw(l *logrus.Logger, data logrus.Fields, errors ErrorTuner, level Level, msg string, fields []Field) {
+	if !l.IsLevelEnabled(level) {
+		return
+	}
+
+	l.WithFields(makeFields(data, errors, fields)).Log(level, msg)
+	if level == FatalLevel {
+		l.Exit(1)
+	}
+}
+
+func (that *logger) Enabled(ctx context.Context, level Level) bool {
+	return that.Logger.IsLevelEnabled(level)
+}
+
+func (that *logger) Tracew(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, nil, that.errors, TraceLevel, msg, fields)
+}
+
+func (that *logger) Debugw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, nil, that.errors, DebugLevel, msg, fields)
+}
+
+func (that *logger) Infow(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, nil, that.errors, InfoLevel, msg, fields)
+}
+
+func (that *logger) Warningw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, nil, that.errors, WarnLevel, msg, fields)
+}
+
+func (that *logger) Errorw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, nil, that.errors, ErrorLevel, msg, fields)
+}
+
+func (that *logger) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, nil, that.errors, FatalLevel, msg, fields)
+}
+
+func (that *logger) Panicw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, nil, that.errors, PanicLevel, msg, fields)
+}
+
+func (that *entry) Enabled(ctx context.Context, level Level) bool {
+	return that.Logger.IsLevelEnabled(level)
+}
+
+func (that *entry) Tracew(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, that.Data, that.logger.errors, TraceLevel, msg, fields)
+}
+
+func (that *entry) Debugw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, that.Data, that.logger.errors, DebugLevel, msg, fields)
+}
+
+func (that *entry) Infow(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, that.Data, that.logger.errors, InfoLevel, msg, fields)
+}
+
+func (that *entry) Warningw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, that.Data, that.logger.errors, WarnLevel, msg, fields)
+}
+
+func (that *entry) Errorw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, that.Data, that.logger.errors, ErrorLevel, msg, fields)
+}
+
+func (that *entry) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, that.Data, that.logger.errors, FatalLevel, msg, fields)
+}
+
+func (that *entry) Panicw(ctx context.Context, msg string, fields ...Field) {
+	logw(that.Logger, that.Data, that.logger.errors, PanicLevel, msg, fields)
+}
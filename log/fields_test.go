@@ -0,0 +1,108 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/Adverax/core/log/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+func newFieldsLogger(t testing.TB, w io.Writer, level Level) Logger {
+	l, err := NewLogrusBuilder().
+		Output(w).
+		Level(level).
+		Formatter(&logrus.JSONFormatter{DisableTimestamp: true}).
+		Build()
+	require.NoError(t, err)
+	return NewLogger(l, nil)
+}
+
+func TestLogger_Infow(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newFieldsLogger(t, buf, InfoLevel)
+	ctx := NewContext(context.Background(), l)
+
+	Infow(
+		ctx,
+		"message",
+		Str("name", "value"),
+		Int("count", 2),
+		Bool("ok", true),
+		Duration("duration", time.Second),
+		Err(errors.New("failure")),
+		Err(nil),
+		Any("list", []int{1, 2}),
+	)
+	Debugw(ctx, "hidden", Str("name", "value"))
+
+	assert.JSONEq(t, `{
+		"level": "info",
+		"msg": "message",
+		"name": "value",
+		"count": 2,
+		"ok": true,
+		"duration": 1000000000,
+		"error": "failure",
+		"list": [1, 2]
+	}`, buf.String())
+}
+
+func TestLogger_InfowWithFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newFieldsLogger(t, buf, InfoLevel)
+	ctx := context.Background()
+
+	Logw(ctx, l.WithField(ctx, "entity", "USER"), InfoLevel, "message", Float64("rate", 0.5))
+
+	assert.JSONEq(t, `{
+		"level": "info",
+		"msg": "message",
+		"entity": "USER",
+		"rate": 0.5
+	}`, buf.String())
+}
+
+func TestInfow_DisabledDoesNotAllocate(t *testing.T) {
+	ctx := NewContext(context.Background(), newFieldsLogger(t, io.Discard, WarnLevel))
+	allocs := testing.AllocsPerRun(100, func() {
+		Infow(ctx, "message", Str("name", "value"), Int("count", 2))
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkInfow_Disabled(b *testing.B) {
+	ctx := NewContext(context.Background(), newFieldsLogger(b, io.Discard, WarnLevel))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Infow(ctx, "message", Str("name", "value"), Int("count", i))
+	}
+}
+
+func BenchmarkInfow_Enabled(b *testing.B) {
+	ctx := NewContext(context.Background(), newFieldsLogger(b, io.Discard, InfoLevel))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Infow(ctx, "message", Str("name", "value"), Int("count", i))
+	}
+}
+
+func BenchmarkWithFields_Disabled(b *testing.B) {
+	ctx := NewContext(context.Background(), newFieldsLogger(b, io.Discard, WarnLevel))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WithFields(ctx, Fields{"name": "value", "count": i}).Info(ctx, "message")
+	}
+}
+
+func BenchmarkWithFields_Enabled(b *testing.B) {
+	ctx := NewContext(context.Background(), newFieldsLogger(b, io.Discard, InfoLevel))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WithFields(ctx, Fields{"name": "value", "count": i}).Info(ctx, "message")
+	}
+}
@@ -0,0 +1,84 @@
+package hooks
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const defaultDialTimeout = 5 * time.Second
+
+// connection is the network connection, that is dialed on demand and redialed after failure.
+type connection struct {
+	network string
+	address string
+	timeout time.Duration
+
+	mx   sync.Mutex
+	conn net.Conn
+}
+
+func newConnection(network, address string, timeout time.Duration) *connection {
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
+	}
+
+	return &connection{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// write writes the data. If the established connection is broken,
+// the data is written once more to the new connection.
+func (that *connection) write(p []byte) error {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	for {
+		fresh := that.conn == nil
+		if fresh {
+			conn, err := net.DialTimeout(that.network, that.address, that.timeout)
+			if err != nil {
+				return err
+			}
+			that.conn = conn
+		}
+
+		_ = that.conn.SetWriteDeadline(time.Now().Add(that.timeout))
+		_, err := that.conn.Write(p)
+		if err == nil {
+			return nil
+		}
+
+		_ = that.conn.Close()
+		that.conn = nil
+		if fresh {
+			return err
+		}
+	}
+}
+
+func (that *connection) close() error {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if that.conn == nil {
+		return nil
+	}
+
+	err := that.conn.Close()
+	that.conn = nil
+	return err
+}
+
+// isStream returns true for the stream networks, that need framing of the messages.
+func isStream(network string) bool {
+	switch network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return false
+	default:
+		return true
+	}
+}
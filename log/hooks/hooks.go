@@ -0,0 +1,45 @@
+package hooks
+
+import (
+	"github.com/Adverax/core/log/logrus"
+	"io"
+)
+
+// WriterHook writes entries formatted by the formatter to the writer.
+// Wrap slow writers with logger.AsyncWriter, so the hook doesn't block the logger.
+type WriterHook struct {
+	writer    io.Writer
+	formatter logrus.Formatter
+	levels    []logrus.Level
+}
+
+// NewWriterHook makes hook of the levels (all levels by default).
+func NewWriterHook(writer io.Writer, formatter logrus.Formatter, levels ...logrus.Level) *WriterHook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+
+	return &WriterHook{
+		writer:    writer,
+		formatter: formatter,
+		levels:    levels,
+	}
+}
+
+func (that *WriterHook) Levels() []logrus.Level {
+	return that.levels
+}
+
+func (that *WriterHook) Fire(entry *logrus.Entry) error {
+	data, err := that.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	if w, ok := that.writer.(logrus.LevelWriter); ok {
+		_, err = w.WriteLevel(entry.Level, data)
+	} else {
+		_, err = that.writer.Write(data)
+	}
+	return err
+}
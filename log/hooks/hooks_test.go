@@ -0,0 +1,396 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"github.com/Adverax/core/log/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newEntry(level logrus.Level, msg string, fields logrus.Fields) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.New())
+	entry.Level = level
+	entry.Message = msg
+	entry.Data = fields
+	entry.Time = time.Date(2024, 5, 1, 10, 20, 30, 123456000, time.UTC)
+	return entry
+}
+
+var syslogMessage = regexp.MustCompile(`^<(\d+)>1 (\S+) (\S+) (\S+) (\d+) (\S+) (-|\[.*\]) (.*)$`)
+
+func TestSyslogHook_Format(t *testing.T) {
+	facility := FacilityLocal3
+	hook := NewSyslogHook(SyslogOptions{
+		Network:  "udp",
+		Address:  "127.0.0.1:514",
+		Facility: &facility,
+		Hostname: "web 1",
+		AppName:  "app",
+	})
+
+	msg := string(hook.Format(newEntry(logrus.WarnLevel, "disk is full", logrus.Fields{
+		logrus.FieldKeyEntity: "storage",
+		"path":                `c:\tmp "x"`,
+		"error":               io.EOF,
+	})))
+
+	m := syslogMessage.FindStringSubmatch(msg)
+	require.NotNil(t, m, msg)
+	assert.Equal(t, strconv.Itoa(19*8+4), m[1])
+	assert.Equal(t, "2024-05-01T10:20:30.123456Z", m[2])
+	assert.Equal(t, "web_1", m[3])
+	assert.Equal(t, "app", m[4])
+	assert.Equal(t, "storage", m[6])
+	assert.Equal(t, `[fields@32473 entity="storage" error="EOF" path="c:\\tmp \"x\""]`, m[7])
+	assert.Equal(t, "disk is full", m[8])
+
+	msg = string(hook.Format(newEntry(logrus.ErrorLevel, "failed", nil)))
+	m = syslogMessage.FindStringSubmatch(msg)
+	require.NotNil(t, m, msg)
+	assert.Equal(t, strconv.Itoa(19*8+3), m[1])
+	assert.Equal(t, "-", m[6])
+	assert.Equal(t, "-", m[7])
+}
+
+func TestSyslogHook_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	hook := NewSyslogHook(SyslogOptions{Network: "udp", Address: conn.LocalAddr().String()})
+	defer hook.Close()
+
+	require.NoError(t, hook.Fire(newEntry(logrus.InfoLevel, "hello", nil)))
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<14>1 "), msg)
+	assert.True(t, strings.HasSuffix(msg, " - hello"), msg)
+}
+
+func TestSyslogHook_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	frames := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		r := bufio.NewReader(conn)
+		frames <- []string{readFrame(t, r), readFrame(t, r)}
+	}()
+
+	hook := NewSyslogHook(SyslogOptions{Network: "tcp", Address: ln.Addr().String()})
+	defer hook.Close()
+
+	require.NoError(t, hook.Fire(newEntry(logrus.InfoLevel, "first\nline", nil)))
+	require.NoError(t, hook.Fire(newEntry(logrus.DebugLevel, "second", nil)))
+
+	select {
+	case got := <-frames:
+		assert.True(t, strings.HasSuffix(got[0], " - first\nline"), got[0])
+		assert.True(t, strings.HasPrefix(got[1], "<15>1 "), got[1])
+		assert.True(t, strings.HasSuffix(got[1], " - second"), got[1])
+	case <-time.After(5 * time.Second):
+		t.Fatal("frames are not received")
+	}
+}
+
+func TestSyslogHook_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syslog.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	hook := NewSyslogHook(SyslogOptions{Network: "unixgram", Address: path})
+	defer hook.Close()
+
+	require.NoError(t, hook.Fire(newEntry(logrus.ErrorLevel, "boom", nil)))
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(buf[:n]), "<11>1 "), string(buf[:n]))
+}
+
+// readFrame reads the octet counted frame.
+func readFrame(t *testing.T, r *bufio.Reader) string {
+	size, err := r.ReadString(' ')
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(size))
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Error(err)
+		return ""
+	}
+	return string(buf)
+}
+
+func TestTCPWriter_Reconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 100)
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			line, err := r.ReadString('\n')
+			if err == nil {
+				lines <- line
+			}
+			// the server drops the connection after the first line
+			_ = conn.Close()
+		}
+	}()
+
+	w := NewTCPWriter(ln.Addr().String(), time.Second)
+	defer w.Close()
+
+	hook := NewWriterHook(w, &logrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	require.NoError(t, hook.Fire(newEntry(logrus.InfoLevel, "first", nil)))
+
+	select {
+	case line := <-lines:
+		assert.Contains(t, line, "msg=first")
+	case <-time.After(5 * time.Second):
+		t.Fatal("first line is not received")
+	}
+
+	// writes to the dropped connection fail only after the peer resets it
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		_, _ = w.Write([]byte("second"))
+		select {
+		case line := <-lines:
+			assert.Equal(t, "second\n", line)
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	t.Fatal("writer is not reconnected")
+}
+
+type collector struct {
+	mx       sync.Mutex
+	failures int
+	status   int
+	requests int
+	bodies   []string
+	encoding string
+}
+
+func (that *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.requests++
+	if that.failures > 0 {
+		that.failures--
+		w.WriteHeader(that.status)
+		return
+	}
+
+	var reader io.Reader = r.Body
+	that.encoding = r.Header.Get("Content-Encoding")
+	if that.encoding == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reader = zr
+	}
+	body, _ := io.ReadAll(reader)
+	that.bodies = append(that.bodies, string(body))
+}
+
+func (that *collector) fail(count, status int) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+	that.failures = count
+	that.status = status
+}
+
+func (that *collector) snapshot() (int, []string) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+	return that.requests, append([]string(nil), that.bodies...)
+}
+
+func TestHTTPWriter_BatchWithRetries(t *testing.T) {
+	c := &collector{}
+	c.fail(2, http.StatusServiceUnavailable)
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	w := NewHTTPWriter(HTTPOptions{
+		URL:           srv.URL,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		Backoff:       time.Millisecond,
+		Gzip:          true,
+	})
+
+	_, _ = w.Write([]byte(`{"msg":"a"}` + "\n"))
+	_, _ = w.Write([]byte(`{"msg":"b"}`))
+
+	require.Eventually(t, func() bool {
+		_, bodies := c.snapshot()
+		return len(bodies) == 1
+	}, 5*time.Second, 5*time.Millisecond)
+
+	requests, bodies := c.snapshot()
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, "{\"msg\":\"a\"}\n{\"msg\":\"b\"}\n", bodies[0])
+	assert.Equal(t, "gzip", c.encoding)
+
+	require.NoError(t, w.Close())
+	assert.Equal(t, int64(0), w.Dropped())
+}
+
+func TestHTTPWriter_Spill(t *testing.T) {
+	c := &collector{}
+	c.fail(100, http.StatusInternalServerError)
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "spill")
+	retries := 0
+	w := NewHTTPWriter(HTTPOptions{
+		URL:           srv.URL,
+		FlushInterval: time.Hour,
+		Retries:       &retries,
+		SpillDir:      dir,
+		MaxSpillFiles: 2,
+	})
+
+	for _, msg := range []string{"a", "b", "c"} {
+		_, _ = w.Write([]byte(msg))
+		assert.Error(t, w.Flush())
+	}
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, int64(1), w.Dropped())
+
+	c.fail(0, 0)
+	_, _ = w.Write([]byte("d"))
+	require.NoError(t, w.Flush())
+
+	_, bodies := c.snapshot()
+	assert.Equal(t, []string{"d\n", "b\n", "c\n"}, bodies)
+
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte("e"))
+	assert.ErrorIs(t, err, ErrHTTPWriterClosed)
+}
+
+func TestHTTPWriter_Rejected(t *testing.T) {
+	c := &collector{}
+	c.fail(1, http.StatusBadRequest)
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	w := NewHTTPWriter(HTTPOptions{URL: srv.URL, FlushInterval: time.Hour, SpillDir: dir})
+	defer w.Close()
+
+	_, _ = w.Write([]byte("bad"))
+	err := w.Flush()
+	var statusErr *HTTPStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+
+	// rejected batches are not retried and not spilled
+	requests, _ := c.snapshot()
+	assert.Equal(t, 1, requests)
+	files, _ := os.ReadDir(dir)
+	assert.Empty(t, files)
+	assert.Equal(t, int64(1), w.Dropped())
+}
+
+func TestHTTPWriter_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	retries := 0
+	w := NewHTTPWriter(HTTPOptions{
+		URL:           srv.URL,
+		Timeout:       20 * time.Millisecond,
+		FlushInterval: time.Hour,
+		Retries:       &retries,
+	})
+
+	_, _ = w.Write([]byte("hanging"))
+	closed := make(chan error, 1)
+	go func() {
+		closed <- w.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("close hangs on the collector")
+	}
+	assert.Equal(t, int64(1), w.Dropped())
+}
+
+func TestWriterHook_Levels(t *testing.T) {
+	var b bytes.Buffer
+	l := logrus.New()
+	l.Out = io.Discard
+	l.AddHook(NewWriterHook(&b, &logrus.JSONFormatter{DisableTimestamp: true}, logrus.ErrorLevel))
+
+	l.Info("skipped")
+	l.Error("sent")
+
+	assert.Equal(t, "{\"level\":\"error\",\"msg\":\"sent\"}\n", b.String())
+}
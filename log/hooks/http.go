@@ -0,0 +1,422 @@
+package hooks
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout       = 10 * time.Second
+	defaultHTTPBatchSize     = 100
+	defaultHTTPFlushInterval = time.Second
+	defaultHTTPRetries       = 3
+	defaultHTTPBackoff       = 100 * time.Millisecond
+	defaultHTTPMaxSpillFiles = 100
+	defaultHTTPContentType   = "application/x-ndjson"
+
+	spillExtension = ".batch"
+)
+
+var (
+	ErrHTTPWriterClosed = errors.New("http writer is closed")
+)
+
+// HTTPStatusError is returned, when the server rejects the batch.
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (that *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected status of the log batch: %d", that.StatusCode)
+}
+
+// temporary returns true, if the batch may be accepted later.
+func (that *HTTPStatusError) temporary() bool {
+	return that.StatusCode >= 500 ||
+		that.StatusCode == http.StatusRequestTimeout ||
+		that.StatusCode == http.StatusTooManyRequests
+}
+
+type HTTPOptions struct {
+	// URL of the collector, that receives the batches with POST requests.
+	URL string
+	// Client sends the requests. Default is http.DefaultClient.
+	Client *http.Client
+	// Timeout limits duration of each request, so Flush and Close don't hang on the collector. Default is 10 seconds.
+	Timeout time.Duration
+	// Header is added to the requests.
+	Header http.Header
+	// ContentType of the batches. Default is "application/x-ndjson".
+	ContentType string
+	// BatchSize is the count of the entries, that triggers sending of the batch. Default is 100.
+	BatchSize int
+	// FlushInterval is the max time the entries wait in the batch. Default is 1 second.
+	FlushInterval time.Duration
+	// Retries is the count of the repeated attempts to send the batch. Default is 3.
+	Retries *int
+	// Backoff is the delay before the first retry, it is doubled for each next retry. Default is 100ms.
+	Backoff time.Duration
+	// Gzip compresses the body of the requests.
+	Gzip bool
+	// SpillDir is the directory, where the failed batches are stored until the collector is available.
+	// The failed batches are dropped, if it is empty.
+	SpillDir string
+	// MaxSpillFiles limits the count of the stored batches, the oldest are dropped. Default is 100.
+	MaxSpillFiles int
+}
+
+// HTTPWriter is io.WriteCloser, that sends the written entries to the collector in batches.
+// Writes are buffered, the batches are sent in background with retries.
+// Batches, that are failed, are spilled to disk and resent after the next successful send.
+type HTTPWriter struct {
+	options HTTPOptions
+	retries int
+
+	mx     sync.Mutex
+	batch  bytes.Buffer
+	count  int
+	closed bool
+	err    error
+
+	sendMx  sync.Mutex
+	seq     uint64
+	dropped int64
+
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func NewHTTPWriter(options HTTPOptions) *HTTPWriter {
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultHTTPTimeout
+	}
+	if options.ContentType == "" {
+		options.ContentType = defaultHTTPContentType
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultHTTPBatchSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = defaultHTTPFlushInterval
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = defaultHTTPBackoff
+	}
+	if options.MaxSpillFiles <= 0 {
+		options.MaxSpillFiles = defaultHTTPMaxSpillFiles
+	}
+
+	retries := defaultHTTPRetries
+	if options.Retries != nil {
+		retries = *options.Retries
+	}
+
+	w := &HTTPWriter{
+		options: options,
+		retries: retries,
+		flush:   make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go w.serve()
+
+	return w
+}
+
+// Write appends the entry to the batch. The entry is terminated with the new line.
+func (that *HTTPWriter) Write(p []byte) (int, error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if that.closed {
+		return 0, ErrHTTPWriterClosed
+	}
+
+	that.batch.Write(p)
+	if len(p) == 0 || p[len(p)-1] != '\n' {
+		that.batch.WriteByte('\n')
+	}
+	that.count++
+
+	if that.count >= that.options.BatchSize {
+		select {
+		case that.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns count of the batches, that are lost.
+func (that *HTTPWriter) Dropped() int64 {
+	return atomic.LoadInt64(&that.dropped)
+}
+
+// Flush sends the current batch and the spilled batches.
+// It returns the last error of sending.
+func (that *HTTPWriter) Flush() error {
+	that.send()
+
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	err := that.err
+	that.err = nil
+	return err
+}
+
+// Close sends the rest of the entries and stops the writer.
+// The subsequent calls do nothing and return nil.
+func (that *HTTPWriter) Close() error {
+	that.mx.Lock()
+	if that.closed {
+		that.mx.Unlock()
+		return nil
+	}
+	that.closed = true
+	that.mx.Unlock()
+
+	close(that.stop)
+	<-that.done
+
+	return that.Flush()
+}
+
+func (that *HTTPWriter) serve() {
+	defer close(that.done)
+
+	ticker := time.NewTicker(that.options.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-that.stop:
+			return
+		case <-ticker.C:
+		case <-that.flush:
+		}
+
+		that.send()
+	}
+}
+
+func (that *HTTPWriter) send() {
+	that.sendMx.Lock()
+	defer that.sendMx.Unlock()
+
+	that.mx.Lock()
+	body := make([]byte, that.batch.Len())
+	copy(body, that.batch.Bytes())
+	that.batch.Reset()
+	that.count = 0
+	that.mx.Unlock()
+
+	if len(body) != 0 {
+		if err := that.post(body); err != nil {
+			that.fail(body, err)
+			return
+		}
+	}
+
+	that.resend()
+}
+
+// resend sends the spilled batches from the oldest until the first failure.
+func (that *HTTPWriter) resend() {
+	files, err := that.spilled()
+	if err != nil {
+		that.report(err)
+		return
+	}
+
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			that.report(err)
+			return
+		}
+
+		if err := that.post(body); err != nil {
+			if isTemporary(err) {
+				that.report(err)
+				return
+			}
+			atomic.AddInt64(&that.dropped, 1)
+			that.report(err)
+		}
+
+		if err := os.Remove(file); err != nil {
+			that.report(err)
+			return
+		}
+	}
+}
+
+// fail spills the failed batch, if it may be accepted later.
+func (that *HTTPWriter) fail(body []byte, err error) {
+	that.report(err)
+
+	if that.options.SpillDir == "" || !isTemporary(err) {
+		atomic.AddInt64(&that.dropped, 1)
+		return
+	}
+
+	if err := that.spill(body); err != nil {
+		atomic.AddInt64(&that.dropped, 1)
+		that.report(err)
+	}
+}
+
+func (that *HTTPWriter) spill(body []byte) error {
+	dir := that.options.SpillDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	that.seq++
+	name := filepath.Join(dir, fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), that.seq, spillExtension))
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	files, err := that.spilled()
+	if err != nil {
+		return err
+	}
+	for len(files) > that.options.MaxSpillFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		atomic.AddInt64(&that.dropped, 1)
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// spilled returns the spilled batches from the oldest.
+func (that *HTTPWriter) spilled() ([]string, error) {
+	if that.options.SpillDir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(that.options.SpillDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), spillExtension) {
+			files = append(files, filepath.Join(that.options.SpillDir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// post sends the batch, repeating temporary failures with the exponential backoff.
+func (that *HTTPWriter) post(body []byte) error {
+	backoff := that.options.Backoff
+	for attempt := 0; ; attempt++ {
+		err := that.postOnce(body)
+		if err == nil || !isTemporary(err) || attempt >= that.retries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-that.stop:
+			// the rest of the batch will be spilled
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (that *HTTPWriter) postOnce(body []byte) error {
+	var reader io.Reader = bytes.NewReader(body)
+	if that.options.Gzip {
+		var b bytes.Buffer
+		zw := gzip.NewWriter(&b)
+		if _, err := zw.Write(body); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		reader = &b
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), that.options.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, that.options.URL, reader)
+	if err != nil {
+		return err
+	}
+	for k, vs := range that.options.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", that.options.ContentType)
+	if that.options.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := that.options.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &HTTPStatusError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+func (that *HTTPWriter) report(err error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.err = err
+}
+
+// isTemporary returns true for network failures and temporary statuses.
+func isTemporary(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.temporary()
+	}
+	return true
+}
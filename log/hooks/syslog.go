@@ -0,0 +1,237 @@
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Adverax/core/log/logrus"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	syslogVersion         = 1
+	syslogTimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+	syslogNil             = "-"
+
+	// DefaultSyslogSDID is the id of the structured data element with the fields of the entry.
+	// 32473 is the enterprise number reserved for documentation.
+	DefaultSyslogSDID = "fields@32473"
+)
+
+// Syslog facilities.
+const (
+	FacilityKern   = 0
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityAuth   = 4
+	FacilityLocal0 = 16
+	FacilityLocal1 = 17
+	FacilityLocal2 = 18
+	FacilityLocal3 = 19
+	FacilityLocal4 = 20
+	FacilityLocal5 = 21
+	FacilityLocal6 = 22
+	FacilityLocal7 = 23
+)
+
+// syslogSeverities maps levels to the syslog severities.
+var syslogSeverities = map[logrus.Level]int{
+	logrus.PanicLevel: 2,
+	logrus.FatalLevel: 2,
+	logrus.ErrorLevel: 3,
+	logrus.WarnLevel:  4,
+	logrus.InfoLevel:  6,
+	logrus.DebugLevel: 7,
+	logrus.TraceLevel: 7,
+}
+
+type SyslogOptions struct {
+	// Network is "udp", "tcp", "unix" or "unixgram".
+	Network string
+	// Address of the syslog server or path of the unix socket.
+	Address string
+	// Facility of the messages. Default is FacilityUser.
+	Facility *int
+	// Hostname of the messages. Default is name of the host.
+	Hostname string
+	// AppName of the messages. Default is name of the executable.
+	AppName string
+	// SDID is the id of the structured data element with the fields. Default is DefaultSyslogSDID.
+	SDID string
+	// Timeout of the dialing and writing.
+	Timeout time.Duration
+	// Levels of the hook. Default is all levels.
+	Levels []logrus.Level
+}
+
+// SyslogHook sends entries to the syslog server in RFC 5424 format.
+// The fields of the entry are sent as the structured data,
+// the entity of the entry becomes MSGID.
+// Messages are framed with octet counting (RFC 6587) over the stream networks.
+type SyslogHook struct {
+	conn     *connection
+	stream   bool
+	facility int
+	hostname string
+	appName  string
+	procID   string
+	sdID     string
+	levels   []logrus.Level
+}
+
+func NewSyslogHook(options SyslogOptions) *SyslogHook {
+	facility := FacilityUser
+	if options.Facility != nil {
+		facility = *options.Facility
+	}
+	if options.Hostname == "" {
+		options.Hostname, _ = os.Hostname()
+	}
+	if options.AppName == "" {
+		options.AppName = filepath.Base(os.Args[0])
+	}
+	if options.SDID == "" {
+		options.SDID = DefaultSyslogSDID
+	}
+	if len(options.Levels) == 0 {
+		options.Levels = logrus.AllLevels
+	}
+
+	return &SyslogHook{
+		conn:     newConnection(options.Network, options.Address, options.Timeout),
+		stream:   isStream(options.Network),
+		facility: facility,
+		hostname: syslogHeader(options.Hostname, 255),
+		appName:  syslogHeader(options.AppName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+		sdID:     syslogHeader(options.SDID, 32),
+		levels:   options.Levels,
+	}
+}
+
+func (that *SyslogHook) Levels() []logrus.Level {
+	return that.levels
+}
+
+func (that *SyslogHook) Fire(entry *logrus.Entry) error {
+	msg := that.Format(entry)
+	if that.stream {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	return that.conn.write(msg)
+}
+
+func (that *SyslogHook) Close() error {
+	return that.conn.close()
+}
+
+// Format returns RFC 5424 message of the entry without framing.
+func (that *SyslogHook) Format(entry *logrus.Entry) []byte {
+	var b bytes.Buffer
+
+	pri := that.facility*8 + syslogSeverities[entry.Level]
+	fmt.Fprintf(&b, "<%d>%d ", pri, syslogVersion)
+
+	if entry.Time.IsZero() {
+		b.WriteString(syslogNil)
+	} else {
+		b.WriteString(entry.Time.Format(syslogTimestampFormat))
+	}
+
+	msgID := syslogNil
+	if v, ok := entry.Data[logrus.FieldKeyEntity]; ok {
+		msgID = syslogHeader(fmt.Sprint(v), 32)
+	}
+
+	for _, h := range []string{that.hostname, that.appName, that.procID, msgID} {
+		b.WriteByte(' ')
+		b.WriteString(h)
+	}
+
+	b.WriteByte(' ')
+	that.writeStructuredData(&b, entry.Data)
+
+	if entry.Message != "" {
+		b.WriteByte(' ')
+		b.WriteString(strings.TrimRight(entry.Message, "\n"))
+	}
+
+	return b.Bytes()
+}
+
+func (that *SyslogHook) writeStructuredData(b *bytes.Buffer, data logrus.Fields) {
+	if len(data) == 0 {
+		b.WriteString(syslogNil)
+		return
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteByte('[')
+	b.WriteString(that.sdID)
+	for _, k := range keys {
+		v := data[k]
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+
+		b.WriteByte(' ')
+		b.WriteString(syslogParamName(k))
+		b.WriteString(`="`)
+		b.WriteString(syslogParamValue(fmt.Sprint(v)))
+		b.WriteByte('"')
+	}
+	b.WriteByte(']')
+}
+
+// syslogHeader returns the header field, that may contain only printable ASCII characters.
+func syslogHeader(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r >= 0x7f {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if s == "" {
+		return syslogNil
+	}
+	if len(s) > max {
+		s = s[:max]
+	}
+	return s
+}
+
+// syslogParamName returns the name of the parameter of the structured data.
+func syslogParamName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r >= 0x7f || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if s == "" {
+		return "_"
+	}
+	if len(s) > 32 {
+		s = s[:32]
+	}
+	return s
+}
+
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogParamValue escapes the value of the parameter of the structured data.
+func syslogParamValue(s string) string {
+	return syslogParamEscaper.Replace(s)
+}
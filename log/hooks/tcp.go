@@ -0,0 +1,33 @@
+package hooks
+
+import (
+	"time"
+)
+
+// TCPWriter is io.WriteCloser, that sends newline delimited entries over TCP.
+// The connection is dialed on the first write and redialed, when it is broken.
+type TCPWriter struct {
+	conn *connection
+}
+
+func NewTCPWriter(address string, timeout time.Duration) *TCPWriter {
+	return &TCPWriter{conn: newConnection("tcp", address, timeout)}
+}
+
+func (that *TCPWriter) Write(p []byte) (int, error) {
+	data := p
+	if len(p) == 0 || p[len(p)-1] != '\n' {
+		data = make([]byte, len(p), len(p)+1)
+		copy(data, p)
+		data = append(data, '\n')
+	}
+
+	if err := that.conn.write(data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (that *TCPWriter) Close() error {
+	return that.conn.close()
+}
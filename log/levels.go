@@ -0,0 +1,468 @@
+package log
+
+import (
+	"context"
+	"github.com/Adverax/core/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Levels describes levels of the registry.
+// Nil level of the logger resets it to the level of the parent.
+type Levels struct {
+	Default *Level            `json:"default,omitempty"`
+	Loggers map[string]*Level `json:"loggers,omitempty"`
+}
+
+// LevelRegistry keeps levels of the named loggers, that can be changed at runtime.
+// Names are hierarchical: logger "bus.channel" inherits level of the logger "bus",
+// unless it has its own level.
+type LevelRegistry struct {
+	mx     sync.RWMutex
+	logger Logger
+	level  Level
+	levels map[string]Level
+	cells  map[string]*uint32
+}
+
+// NewLevelRegistry makes registry of the loggers built by the factory.
+// The base logger is built with TraceLevel, so the registry is the only filter of the entries
+// and any level can be enabled at runtime.
+func NewLevelRegistry(factory LevelBuilder, level Level) (*LevelRegistry, error) {
+	logger, err := factory.NewLogger(TraceLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LevelRegistry{
+		logger: logger,
+		level:  level,
+		levels: make(map[string]Level),
+		cells:  make(map[string]*uint32),
+	}, nil
+}
+
+// Named returns logger of the registry with the name.
+func (that *LevelRegistry) Named(name string) Logger {
+	return that.Wrap(name, that.logger)
+}
+
+// Build returns context logger of the registry with the name (see ContextLoggerFactory.Build).
+func (that *LevelRegistry) Build(name string, context ContextMode) Logger {
+	return NewContextLogger(that.Named(name), context)
+}
+
+// Wrap returns logger, that writes entries of the enabled levels of the name to the logger.
+// The logger must write all levels, that can be enabled at runtime.
+func (that *LevelRegistry) Wrap(name string, logger Logger) Logger {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	cell, ok := that.cells[name]
+	if !ok {
+		cell = new(uint32)
+		atomic.StoreUint32(cell, uint32(that.resolve(name)))
+		that.cells[name] = cell
+	}
+
+	return &NamedLogger{name: name, logger: logger, level: cell}
+}
+
+// Level returns actual level of the name.
+func (that *LevelRegistry) Level(name string) Level {
+	that.mx.RLock()
+	defer that.mx.RUnlock()
+
+	return that.resolve(name)
+}
+
+func (that *LevelRegistry) SetDefaultLevel(level Level) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.level = level
+	that.refresh()
+}
+
+func (that *LevelRegistry) SetLevel(name string, level Level) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.levels[name] = level
+	that.refresh()
+}
+
+// ResetLevel makes the name inherit level of its parent.
+func (that *LevelRegistry) ResetLevel(name string) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	delete(that.levels, name)
+	that.refresh()
+}
+
+// Levels returns default level and actual levels of the known loggers.
+func (that *LevelRegistry) Levels() Levels {
+	that.mx.RLock()
+	defer that.mx.RUnlock()
+
+	level := that.level
+	levels := Levels{
+		Default: &level,
+		Loggers: make(map[string]*Level, len(that.cells)+len(that.levels)),
+	}
+	for name := range that.cells {
+		level := that.resolve(name)
+		levels.Loggers[name] = &level
+	}
+	for name := range that.levels {
+		level := that.resolve(name)
+		levels.Loggers[name] = &level
+	}
+	return levels
+}
+
+// Update applies the levels. If replace is true, the levels of the loggers absent in the update are reset.
+func (that *LevelRegistry) Update(levels Levels, replace bool) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if levels.Default != nil {
+		that.level = *levels.Default
+	}
+	if replace {
+		that.levels = make(map[string]Level, len(levels.Loggers))
+	}
+	for name, level := range levels.Loggers {
+		if level == nil {
+			delete(that.levels, name)
+		} else {
+			that.levels[name] = *level
+		}
+	}
+	that.refresh()
+}
+
+// resolve returns level of the name or its nearest parent.
+func (that *LevelRegistry) resolve(name string) Level {
+	for {
+		if level, ok := that.levels[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return that.level
+		}
+		name = name[:i]
+	}
+}
+
+func (that *LevelRegistry) refresh() {
+	for name, cell := range that.cells {
+		atomic.StoreUint32(cell, uint32(that.resolve(name)))
+	}
+}
+
+// ServeHTTP returns levels on GET and updates them on PUT.
+// Body of the request and response is JSON of the Levels.
+func (that *LevelRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var levels Levels
+		if err := json.Unmarshal(data, &levels); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		that.Update(levels, false)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := json.Marshal(that.Levels())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// Load replaces levels with the levels of the JSON file.
+func (that *LevelRegistry) Load(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var levels Levels
+	if err := json.Unmarshal(data, &levels); err != nil {
+		return err
+	}
+
+	that.Update(levels, true)
+	return nil
+}
+
+// Watch loads levels from the JSON file and reloads them, when the file is changed,
+// until the context is done. Errors of the reload are written to the logger of the registry.
+func (that *LevelRegistry) Watch(ctx context.Context, filename string, interval time.Duration) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if err := that.Load(filename); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		modified, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(filename)
+			if err != nil {
+				that.logger.Errorf(ctx, "can't watch levels %q: %s", filename, err)
+				continue
+			}
+			if info.ModTime().Equal(modified) && info.Size() == size {
+				continue
+			}
+
+			modified, size = info.ModTime(), info.Size()
+			if err := that.Load(filename); err != nil {
+				that.logger.Errorf(ctx, "can't reload levels %q: %s", filename, err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// NamedLogger is Logger, that writes entries of the levels enabled for its name in the LevelRegistry.
+type NamedLogger struct {
+	name   string
+	logger Logger
+	level  *uint32
+}
+
+func (that *NamedLogger) Name() string {
+	return that.name
+}
+
+func (that *NamedLogger) with(logger Logger) Logger {
+	return &NamedLogger{name: that.name, logger: logger, level: that.level}
+}
+
+// enabled checks level of the entry. Fatal and panic entries are never suppressed,
+// because the callers rely on their exit and panic.
+func (that *NamedLogger) enabled(level Level) bool {
+	return level <= FatalLevel || level <= Level(atomic.LoadUint32(that.level))
+}
+
+func (that *NamedLogger) NewContext(ctx context.Context) context.Context {
+	return that.logger.NewContext(ctx)
+}
+
+func (that *NamedLogger) WithField(ctx context.Context, key string, value interface{}) Logger {
+	return that.with(that.logger.WithField(ctx, key, value))
+}
+
+func (that *NamedLogger) WithFields(ctx context.Context, fields Fields) Logger {
+	return that.with(that.logger.WithFields(ctx, fields))
+}
+
+func (that *NamedLogger) WithError(ctx context.Context, err error) Logger {
+	return that.with(that.logger.WithError(ctx, err))
+}
+
+func (that *NamedLogger) Enabled(ctx context.Context, level Level) bool {
+	return that.enabled(level) && IsEnabled(ctx, that.logger, level)
+}
+
+func (that *NamedLogger) Tracef(ctx context.Context, format string, args ...interface{}) {
+	if that.enabled(TraceLevel) {
+		that.logger.Tracef(ctx, format, args...)
+	}
+}
+
+func (that *NamedLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	if that.enabled(DebugLevel) {
+		that.logger.Debugf(ctx, format, args...)
+	}
+}
+
+func (that *NamedLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	if that.enabled(InfoLevel) {
+		that.logger.Infof(ctx, format, args...)
+	}
+}
+
+func (that *NamedLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	if that.enabled(WarnLevel) {
+		that.logger.Warningf(ctx, format, args...)
+	}
+}
+
+func (that *NamedLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	if that.enabled(ErrorLevel) {
+		that.logger.Errorf(ctx, format, args...)
+	}
+}
+
+func (that *NamedLogger) Fatalf(ctx context.Context, format string, args ...interface{}) {
+	if that.enabled(FatalLevel) {
+		that.logger.Fatalf(ctx, format, args...)
+	}
+}
+
+func (that *NamedLogger) Panicf(ctx context.Context, format string, args ...interface{}) {
+	if that.enabled(PanicLevel) {
+		that.logger.Panicf(ctx, format, args...)
+	}
+}
+
+func (that *NamedLogger) Trace(ctx context.Context, args ...interface{}) {
+	if that.enabled(TraceLevel) {
+		that.logger.Trace(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Debug(ctx context.Context, args ...interface{}) {
+	if that.enabled(DebugLevel) {
+		that.logger.Debug(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Info(ctx context.Context, args ...interface{}) {
+	if that.enabled(InfoLevel) {
+		that.logger.Info(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Warning(ctx context.Context, args ...interface{}) {
+	if that.enabled(WarnLevel) {
+		that.logger.Warning(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Error(ctx context.Context, args ...interface{}) {
+	if that.enabled(ErrorLevel) {
+		that.logger.Error(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Fatal(ctx context.Context, args ...interface{}) {
+	if that.enabled(FatalLevel) {
+		that.logger.Fatal(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Panic(ctx context.Context, args ...interface{}) {
+	if that.enabled(PanicLevel) {
+		that.logger.Panic(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Traceln(ctx context.Context, args ...interface{}) {
+	if that.enabled(TraceLevel) {
+		that.logger.Traceln(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Debugln(ctx context.Context, args ...interface{}) {
+	if that.enabled(DebugLevel) {
+		that.logger.Debugln(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Infoln(ctx context.Context, args ...interface{}) {
+	if that.enabled(InfoLevel) {
+		that.logger.Infoln(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Warningln(ctx context.Context, args ...interface{}) {
+	if that.enabled(WarnLevel) {
+		that.logger.Warningln(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Errorln(ctx context.Context, args ...interface{}) {
+	if that.enabled(ErrorLevel) {
+		that.logger.Errorln(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Fatalln(ctx context.Context, args ...interface{}) {
+	if that.enabled(FatalLevel) {
+		that.logger.Fatalln(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Panicln(ctx context.Context, args ...interface{}) {
+	if that.enabled(PanicLevel) {
+		that.logger.Panicln(ctx, args...)
+	}
+}
+
+func (that *NamedLogger) Tracew(ctx context.Context, msg string, fields ...Field) {
+	if that.enabled(TraceLevel) {
+		Logw(ctx, that.logger, TraceLevel, msg, fields...)
+	}
+}
+
+func (that *NamedLogger) Debugw(ctx context.Context, msg string, fields ...Field) {
+	if that.enabled(DebugLevel) {
+		Logw(ctx, that.logger, DebugLevel, msg, fields...)
+	}
+}
+
+func (that *NamedLogger) Infow(ctx context.Context, msg string, fields ...Field) {
+	if that.enabled(InfoLevel) {
+		Logw(ctx, that.logger, InfoLevel, msg, fields...)
+	}
+}
+
+func (that *NamedLogger) Warningw(ctx context.Context, msg string, fields ...Field) {
+	if that.enabled(WarnLevel) {
+		Logw(ctx, that.logger, WarnLevel, msg, fields...)
+	}
+}
+
+func (that *NamedLogger) Errorw(ctx context.Context, msg string, fields ...Field) {
+	if that.enabled(ErrorLevel) {
+		Logw(ctx, that.logger, ErrorLevel, msg, fields...)
+	}
+}
+
+func (that *NamedLogger) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	if that.enabled(FatalLevel) {
+		Logw(ctx, that.logger, FatalLevel, msg, fields...)
+	}
+}
+
+func (that *NamedLogger) Panicw(ctx context.Context, msg string, fields ...Field) {
+	if that.enabled(PanicLevel) {
+		Logw(ctx, that.logger, PanicLevel, msg, fields...)
+	}
+}
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func newLevelRegistry(t *testing.T, w io.Writer, level Level) *LevelRegistry {
+	factory := NewLoggerFactory(nopCloser{w}, func(output io.Writer, level Level) (Logger, error) {
+		return newFieldsLogger(t, output, level), nil
+	})
+	r, err := NewLevelRegistry(factory, level)
+	require.NoError(t, err)
+	return r
+}
+
+func TestLevelRegistry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := newLevelRegistry(t, buf, InfoLevel)
+	ctx := context.Background()
+
+	bus := r.Build("bus", ContextModeNone)
+	channel := r.Named("bus.channel").WithField(ctx, "id", 1)
+
+	channel.Debug(ctx, "hidden")
+	r.SetLevel("bus", DebugLevel)
+	channel.Debug(ctx, "channel")
+	bus.Trace(ctx, "hidden")
+	assert.True(t, IsEnabled(ctx, bus, DebugLevel))
+	assert.False(t, IsEnabled(ctx, bus, TraceLevel))
+
+	r.SetLevel("bus.channel", WarnLevel)
+	channel.Info(ctx, "hidden")
+	Logw(ctx, bus, InfoLevel, "bus")
+
+	r.ResetLevel("bus")
+	assert.Equal(t, WarnLevel, r.Level("bus.channel"))
+	assert.Equal(t, InfoLevel, r.Level("bus.other"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"msg":"channel"`)
+	assert.Contains(t, lines[1], `"msg":"bus"`)
+}
+
+func TestLevelRegistry_ServeHTTP(t *testing.T) {
+	r := newLevelRegistry(t, io.Discard, InfoLevel)
+	r.Named("bus")
+	r.Named("pubsub")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/levels", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"default": "info", "loggers": {"bus": "info", "pubsub": "info"}}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	body := strings.NewReader(`{"default": "warning", "loggers": {"bus": "debug"}}`)
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/levels", body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"default": "warning", "loggers": {"bus": "debug", "pubsub": "warning"}}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	body = strings.NewReader(`{"loggers": {"bus": "verbose"}}`)
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/levels", body))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/levels", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestLevelRegistry_Watch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "levels.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"loggers": {"bus": "debug"}}`), 0644))
+
+	r := newLevelRegistry(t, io.Discard, InfoLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, r.Watch(ctx, filename, time.Millisecond))
+	assert.Equal(t, DebugLevel, r.Level("bus"))
+
+	require.NoError(t, os.WriteFile(filename, []byte(`{"default": "error", "loggers": {"pubsub": "trace"}}`), 0644))
+	assert.Eventually(t, func() bool {
+		return r.Level("pubsub") == TraceLevel
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, ErrorLevel, r.Level("bus"))
+}
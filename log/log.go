@@ -1,3 +1,8 @@
+// Package log provides the context aware leveled logger on top of the logrus fork.
+//
+// Besides the printf-style methods, loggers implementing FieldsLogger accept typed fields,
+// e.g. Infow(ctx, msg, Str("user", name), Int("count", n)). The string field is built by Str,
+// not String, because String is kept as the deprecated alias of StringProvider.
 package log
 
 import (
@@ -17,11 +22,13 @@ const (
 
 const (
 	FieldKeyTraceID  = logrus.FieldKeyTraceID
+	FieldKeySpanID   = logrus.FieldKeySpanID
 	FieldKeyEntity   = logrus.FieldKeyEntity
 	FieldKeyAction   = logrus.FieldKeyAction
 	FieldKeyMethod   = logrus.FieldKeyMethod
 	FieldKeySubject  = logrus.FieldKeySubject
 	FieldKeyData     = logrus.FieldKeyData
+	FieldKeyErrors   = logrus.FieldKeyErrors
 	FieldKeyLogType  = "type"
 	FieldKeyDuration = "duration"
 	FieldKeyCause    = "cause"
@@ -36,6 +43,7 @@ const (
 	EntityFrontHttp = "FRONT-HTTP"
 	EntitySQL       = "SQL"
 	EntityBus       = "BUS"
+	EntityScheduler = "SCHEDULER"
 )
 
 const (
@@ -63,6 +71,8 @@ const (
 
 type Purifier = logrus.Purifier
 
+type FieldPurifier = logrus.FieldPurifier
+
 type Level = logrus.Level
 
 type Fields map[string]interface{}
@@ -99,6 +109,24 @@ type Logger interface {
 	Panicln(ctx context.Context, args ...interface{})
 }
 
+// FieldsLogger is the logger, that supports level checks and typed fields.
+// It is not the part of Logger, so the existing implementations of Logger stay valid.
+// Use IsEnabled and Logw to call it through any Logger.
+type FieldsLogger interface {
+	Logger
+
+	// Enabled returns true if entries of the level are written.
+	Enabled(ctx context.Context, level Level) bool
+
+	Tracew(ctx context.Context, msg string, fields ...Field)
+	Debugw(ctx context.Context, msg string, fields ...Field)
+	Infow(ctx context.Context, msg string, fields ...Field)
+	Warningw(ctx context.Context, msg string, fields ...Field)
+	Errorw(ctx context.Context, msg string, fields ...Field)
+	Fatalw(ctx context.Context, msg string, fields ...Field)
+	Panicw(ctx context.Context, msg string, fields ...Field)
+}
+
 type ErrorTuner interface {
 	TuneError(error) error
 }
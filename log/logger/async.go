@@ -0,0 +1,270 @@
+package logger
+
+import (
+	"errors"
+	"github.com/Adverax/core/log/logrus"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultAsyncSize      = 1024
+	defaultAsyncBatchSize = 64
+)
+
+var ErrWriterClosed = errors.New("writer is closed")
+
+// OverflowPolicy defines behavior of the AsyncWriter, when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the writer until the buffer has free space.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop drops the new entry.
+	OverflowDrop
+	// OverflowDropDebug drops debug and trace entries first: the new one or the oldest buffered one.
+	// Entries of the other levels wait for the free space.
+	OverflowDropDebug
+)
+
+type AsyncOptions struct {
+	// Size is capacity of the buffer in entries.
+	Size int
+	// BatchSize is maximum number of entries, that are joined into the single write.
+	BatchSize int
+	// Overflow is policy of the full buffer.
+	Overflow OverflowPolicy
+}
+
+type asyncRecord struct {
+	level logrus.Level
+	data  []byte
+	seq   uint64
+}
+
+// AsyncWriter is io.WriteCloser, that writes entries to the output in the background.
+// Entries are kept in the bounded ring buffer and written by batches.
+// The writer implements logrus.LevelWriter, so it knows levels of the entries written by the logger.
+type AsyncWriter struct {
+	output    io.Writer
+	batchSize int
+	overflow  OverflowPolicy
+
+	mx      sync.Mutex
+	cond    *sync.Cond
+	ring    []asyncRecord
+	head    int
+	count   int
+	seq     uint64 // sequence number of the last buffered entry
+	done    uint64 // all entries up to the sequence number are handled
+	closed  bool
+	err     error
+	dropped uint64
+	stopped chan struct{}
+}
+
+func NewAsyncWriter(output io.Writer, options AsyncOptions) *AsyncWriter {
+	if options.Size <= 0 {
+		options.Size = defaultAsyncSize
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultAsyncBatchSize
+	}
+
+	w := &AsyncWriter{
+		output:    output,
+		batchSize: options.BatchSize,
+		overflow:  options.Overflow,
+		ring:      make([]asyncRecord, options.Size),
+		stopped:   make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mx)
+	go w.serve()
+	return w
+}
+
+// Write writes entry of the unknown level. Such entries are never dropped by OverflowDropDebug.
+func (w *AsyncWriter) Write(p []byte) (n int, err error) {
+	return w.WriteLevel(logrus.InfoLevel, p)
+}
+
+// WriteLevel copies the entry into the buffer.
+// Errors of the output are returned by the following Flush or Close.
+func (w *AsyncWriter) WriteLevel(level logrus.Level, p []byte) (n int, err error) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	for !w.closed && w.count == len(w.ring) {
+		if !w.makeRoom(level) {
+			atomic.AddUint64(&w.dropped, 1)
+			return len(p), nil
+		}
+		if w.count == len(w.ring) {
+			w.cond.Wait()
+		}
+	}
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	data := make([]byte, len(p))
+	copy(data, p)
+	w.seq++
+	w.ring[(w.head+w.count)%len(w.ring)] = asyncRecord{level: level, data: data, seq: w.seq}
+	w.count++
+	w.cond.Broadcast()
+	return len(p), nil
+}
+
+// makeRoom applies overflow policy to the full buffer.
+// It returns false, if the new entry must be dropped.
+func (w *AsyncWriter) makeRoom(level logrus.Level) bool {
+	switch w.overflow {
+	case OverflowDrop:
+		return false
+	case OverflowDropDebug:
+		if isDebug(level) {
+			return false
+		}
+		if w.evictDebug() {
+			atomic.AddUint64(&w.dropped, 1)
+		}
+	}
+
+	return true
+}
+
+// evictDebug removes the oldest debug or trace entry from the buffer.
+func (w *AsyncWriter) evictDebug() bool {
+	for i := 0; i < w.count; i++ {
+		if !isDebug(w.ring[(w.head+i)%len(w.ring)].level) {
+			continue
+		}
+
+		for j := i; j < w.count-1; j++ {
+			w.ring[(w.head+j)%len(w.ring)] = w.ring[(w.head+j+1)%len(w.ring)]
+		}
+		w.count--
+		w.ring[(w.head+w.count)%len(w.ring)] = asyncRecord{}
+		return true
+	}
+
+	return false
+}
+
+// Dropped returns number of the dropped entries.
+func (w *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Flush waits until all entries buffered before the call are written to the output.
+// It returns the first error of the output since the previous Flush.
+func (w *AsyncWriter) Flush() error {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	seq := w.seq
+	for w.done < seq && !w.isStopped() {
+		w.cond.Wait()
+	}
+
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// Close writes all buffered entries and closes the output, if it is io.Closer.
+// Entries written after Close are rejected with ErrWriterClosed.
+func (w *AsyncWriter) Close() error {
+	w.mx.Lock()
+	if w.closed {
+		w.mx.Unlock()
+		<-w.stopped
+		return nil
+	}
+	w.closed = true
+	w.cond.Broadcast()
+	w.mx.Unlock()
+
+	<-w.stopped
+
+	w.mx.Lock()
+	err := w.err
+	w.err = nil
+	w.mx.Unlock()
+
+	if c, ok := w.output.(io.Closer); ok {
+		if e := c.Close(); err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+func (w *AsyncWriter) isStopped() bool {
+	select {
+	case <-w.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *AsyncWriter) serve() {
+	defer func() {
+		w.mx.Lock()
+		close(w.stopped)
+		w.cond.Broadcast()
+		w.mx.Unlock()
+	}()
+
+	var batch []byte
+	for {
+		w.mx.Lock()
+		for w.count == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if w.count == 0 {
+			w.mx.Unlock()
+			return
+		}
+
+		batch = batch[:0]
+		n := w.count
+		if n > w.batchSize {
+			n = w.batchSize
+		}
+		var last uint64
+		for i := 0; i < n; i++ {
+			rec := &w.ring[w.head]
+			batch = append(batch, rec.data...)
+			last = rec.seq
+			*rec = asyncRecord{}
+			w.head = (w.head + 1) % len(w.ring)
+		}
+		w.count -= n
+		if w.count == 0 {
+			// the evicted entries are handled too
+			last = w.seq
+		}
+		w.cond.Broadcast()
+		w.mx.Unlock()
+
+		_, err := w.output.Write(batch)
+
+		w.mx.Lock()
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+		w.done = last
+		w.cond.Broadcast()
+		w.mx.Unlock()
+	}
+}
+
+func isDebug(level logrus.Level) bool {
+	return level >= logrus.DebugLevel
+}
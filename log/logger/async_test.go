@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/Adverax/core/log/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type gatedWriter struct {
+	mx      sync.Mutex
+	buf     bytes.Buffer
+	entered chan struct{}
+	gate    chan struct{}
+	err     error
+	closed  bool
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{
+		entered: make(chan struct{}, 100),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (that *gatedWriter) Write(p []byte) (int, error) {
+	that.entered <- struct{}{}
+	<-that.gate
+
+	that.mx.Lock()
+	defer that.mx.Unlock()
+	that.buf.Write(p)
+	return len(p), that.err
+}
+
+func (that *gatedWriter) Close() error {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+	that.closed = true
+	return nil
+}
+
+func (that *gatedWriter) String() string {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+	return that.buf.String()
+}
+
+func TestAsyncWriter_Close(t *testing.T) {
+	output := newGatedWriter()
+	close(output.gate)
+	w := NewAsyncWriter(output, AsyncOptions{Size: 16, BatchSize: 4})
+
+	var expected strings.Builder
+	for i := 0; i < 100; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		expected.WriteString(line)
+		_, err := w.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+	assert.Equal(t, expected.String(), output.String())
+	assert.True(t, output.closed)
+	assert.Equal(t, uint64(0), w.Dropped())
+
+	_, err := w.Write([]byte("late\n"))
+	assert.ErrorIs(t, err, ErrWriterClosed)
+}
+
+func TestAsyncWriter_OverflowDrop(t *testing.T) {
+	output := newGatedWriter()
+	w := NewAsyncWriter(output, AsyncOptions{Size: 2, BatchSize: 1, Overflow: OverflowDrop})
+
+	_, _ = w.Write([]byte("a\n"))
+	<-output.entered
+	_, _ = w.Write([]byte("b\n"))
+	_, _ = w.Write([]byte("c\n"))
+	n, err := w.Write([]byte("d\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, uint64(1), w.Dropped())
+
+	close(output.gate)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "a\nb\nc\n", output.String())
+}
+
+func TestAsyncWriter_OverflowDropDebug(t *testing.T) {
+	output := newGatedWriter()
+	w := NewAsyncWriter(output, AsyncOptions{Size: 2, BatchSize: 1, Overflow: OverflowDropDebug})
+
+	_, _ = w.WriteLevel(logrus.InfoLevel, []byte("first\n"))
+	<-output.entered
+	_, _ = w.WriteLevel(logrus.DebugLevel, []byte("debug\n"))
+	_, _ = w.WriteLevel(logrus.InfoLevel, []byte("info\n"))
+	_, _ = w.WriteLevel(logrus.ErrorLevel, []byte("error\n"))
+	_, _ = w.WriteLevel(logrus.TraceLevel, []byte("trace\n"))
+	assert.Equal(t, uint64(2), w.Dropped())
+
+	close(output.gate)
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "first\ninfo\nerror\n", output.String())
+	require.NoError(t, w.Close())
+}
+
+func TestAsyncWriter_OverflowBlock(t *testing.T) {
+	output := newGatedWriter()
+	w := NewAsyncWriter(output, AsyncOptions{Size: 1, BatchSize: 1})
+
+	_, _ = w.Write([]byte("a\n"))
+	<-output.entered
+	_, _ = w.Write([]byte("b\n"))
+
+	written := make(chan struct{})
+	go func() {
+		_, _ = w.Write([]byte("c\n"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("write doesn't wait for free space")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(output.gate)
+	<-written
+	require.NoError(t, w.Close())
+	assert.Equal(t, "a\nb\nc\n", output.String())
+	assert.Equal(t, uint64(0), w.Dropped())
+}
+
+func TestAsyncWriter_FlushReturnsError(t *testing.T) {
+	output := newGatedWriter()
+	output.err = errors.New("disk is full")
+	close(output.gate)
+	w := NewAsyncWriter(output, AsyncOptions{})
+
+	_, _ = w.Write([]byte("a\n"))
+	assert.EqualError(t, w.Flush(), "disk is full")
+	assert.NoError(t, w.Flush())
+	require.NoError(t, w.Close())
+}
+
+func TestAsyncWriter_ReceivesLevelFromLogger(t *testing.T) {
+	output := newGatedWriter()
+	w := NewAsyncWriter(output, AsyncOptions{Size: 1, BatchSize: 1, Overflow: OverflowDropDebug})
+	l := logrus.New()
+	l.SetOutput(w)
+	l.SetLevel(logrus.DebugLevel)
+
+	l.Info("first")
+	<-output.entered
+	l.Info("second")
+	l.Debug("dropped")
+	assert.Equal(t, uint64(1), w.Dropped())
+
+	close(output.gate)
+	require.NoError(t, w.Close())
+}
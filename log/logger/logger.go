@@ -1,7 +1,6 @@
 package logger
 
 import (
-	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -32,10 +31,21 @@ type Logger struct {
 	MaxBackups int `json:"maxbackups"`
 	// использовать локальное время компа или UTC ддля формирования имени файла
 	LocalTime bool `json:"localtime"`
-
-	size int64
-	file *os.File
-	mu   sync.Mutex
+	// периодичность ротации. ротация происходит по первому из событий: превышение размера или смена периода
+	Rotation RotationInterval `json:"rotation"`
+	// путь к символической ссылке на текущий файл лога. если не указан - ссылка не создается
+	Symlink string `json:"symlink"`
+	// упаковщик бекапов. если не указан - используется gzip
+	Compressor Compressor `json:"-"`
+	// обработчик ошибок фоновой упаковки и чистки бекапов. если не указан - ошибки пишутся в stderr
+	OnError func(err error) `json:"-"`
+
+	size      int64
+	file      *os.File
+	rotateAt  time.Time
+	mu        sync.Mutex
+	archiveMu sync.Mutex
+	archives  sync.WaitGroup
 }
 
 // InitLog - initialization logger
@@ -68,6 +78,10 @@ func (l *Logger) openExistingOrNew(writeLen int) error {
 	if info.Size()+int64(writeLen) >= l.maxSize() {
 		return l.rotate()
 	}
+	// файл остался с прошлого периода
+	if l.Rotation != RotateNever && info.ModTime().Before(l.Rotation.start(l.now())) {
+		return l.rotate()
+	}
 
 	file, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
@@ -75,51 +89,10 @@ func (l *Logger) openExistingOrNew(writeLen int) error {
 	}
 	l.file = file
 	l.size = info.Size()
-	return nil
+	l.rotateAt = l.Rotation.next(l.now())
+	return l.link()
 }
 
-func (l *Logger) compress(name string) {
-	inFile := name
-	outFile := name + ".gz"
-	zipHandle, err := os.OpenFile(outFile, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
-	if err != nil {
-		fmt.Println("[CompressFile ERROR] Opening file:", err)
-	}
-	defer zipHandle.Close()
-	zipWriter, err := gzip.NewWriterLevel(zipHandle, 9)
-	if err != nil {
-		fmt.Println("[CompressFile ERROR] New gzip writer:", err)
-	}
-	var b []byte
-	inReader, err := os.OpenFile(inFile, os.O_RDONLY, 0666)
-	if err != nil {
-		fmt.Println("[CompressFile ERROR] Opening old log file:", err)
-		return
-	}
-	defer inReader.Close()
-	b, err = ioutil.ReadAll(inReader)
-	if err != nil {
-		fmt.Println("[CompressFile ERROR] Read old log:", err)
-		return
-	}
-	_, err = zipWriter.Write(b)
-	if err != nil {
-		fmt.Println("[CompressFile ERROR] Writing:", err)
-		return
-	}
-	err = zipWriter.Close()
-	if err != nil {
-		fmt.Println("[CompressFile ERROR] Closing zip writer:", err)
-		return
-	}
-}
-func (l *Logger) removeFile(name string) {
-	err := os.Remove(name)
-	if err != nil {
-		fmt.Println("Delete old log fileError :", name, "  ", err)
-		return
-	}
-}
 func (l *Logger) openNew() error {
 	err := os.MkdirAll(l.dir(), 0744)
 	if err != nil {
@@ -135,8 +108,8 @@ func (l *Logger) openNew() error {
 		if err := os.Rename(name, newname); err != nil {
 			return fmt.Errorf("can't rename log file: %s", err)
 		}
-		l.compress(newname)
-		l.removeFile(newname)
+		// упаковка и чистка бекапов в фоне, чтобы не блокировать запись
+		l.archive(newname)
 		// работает только в  linux
 		if err := chown(name, info); err != nil {
 			return err
@@ -149,12 +122,20 @@ func (l *Logger) openNew() error {
 	}
 	l.file = f
 	l.size = 0
-	return nil
+	l.rotateAt = l.Rotation.next(l.now())
+	return l.link()
 }
 
-// получение времени вынесено в отдельную ф-цию исключительно для отладки
-func currentTime() time.Time {
-	return time.Now()
+// получение времени вынесено в отдельную переменную исключительно для отладки
+var currentTime = time.Now
+
+// now - текущее время в зоне, которая используется для имен бекапов
+func (l *Logger) now() time.Time {
+	t := currentTime()
+	if !l.LocalTime {
+		t = t.UTC()
+	}
+	return t
 }
 
 // backupName - возвращает имя файла бекапа
@@ -185,7 +166,7 @@ func (l *Logger) write(p []byte) (n int, err error) {
 			return 0, err
 		}
 	}
-	if l.size+writeLen > l.maxSize() {
+	if l.size+writeLen > l.maxSize() || l.isExpired() {
 		if err := l.rotate(); err != nil {
 			return 0, err
 		}
@@ -243,10 +224,12 @@ func (l *Logger) rotate() error {
 		return err
 	}
 
-	if err := l.openNew(); err != nil {
-		return err
-	}
-	return l.cleanup()
+	return l.openNew()
+}
+
+// isExpired - истек ли период текущего файла
+func (l *Logger) isExpired() bool {
+	return !l.rotateAt.IsZero() && !l.now().Before(l.rotateAt)
 }
 
 // Rotate - бекапим текущий файл и создаем новый...и чистим старые файлы
@@ -257,10 +240,13 @@ func (l *Logger) Rotate() error {
 }
 
 // Close - закрытие файла-необходимо для поддержки интерфейса io.Closer
+// дожидается завершения фоновой упаковки бекапов
 func (l *Logger) Close() error {
 	l.mu.Lock()
-	defer l.mu.Unlock()
-	return l.close()
+	err := l.close()
+	l.mu.Unlock()
+	l.archives.Wait()
+	return err
 }
 
 // закрываем текущий файл
@@ -302,19 +288,18 @@ func (l *Logger) cleanup() error {
 		}
 	}
 
-	if len(deletes) == 0 {
-		return nil
-	}
 	// удаляем все файлы по созданному списку
-	go deleteAll(l.dir(), deletes)
-
-	return nil
+	return deleteAll(l.dir(), deletes)
 }
 
-func deleteAll(dir string, files []logInfo) {
+func deleteAll(dir string, files []logInfo) error {
+	var err error
 	for _, f := range files {
-		_ = os.Remove(filepath.Join(dir, f.Name()))
+		if e := os.Remove(filepath.Join(dir, f.Name())); e != nil && err == nil {
+			err = e
+		}
 	}
+	return err
 }
 
 func (l *Logger) prefixAndExt() (prefix, ext string) {
@@ -329,8 +314,8 @@ func (l *Logger) timeFromName(filename, prefix, ext string) string {
 		return ""
 	}
 	filename = filename[len(prefix):]
-	if strings.HasSuffix(filename, ext+".gz") {
-		ext = ext + ".gz" //коректировка определения суфикса у упакованых файлов
+	if packed := ext + l.compressor().Extension(); strings.HasSuffix(filename, packed) {
+		ext = packed //коректировка определения суфикса у упакованых файлов
 	}
 	if !strings.HasSuffix(filename, ext) {
 		return ""
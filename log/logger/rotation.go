@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// RotationInterval is period of the time based rotation.
+type RotationInterval string
+
+const (
+	RotateNever  RotationInterval = ""
+	RotateHourly RotationInterval = "hourly"
+	RotateDaily  RotationInterval = "daily"
+)
+
+// start returns beginning of the period, that contains the time.
+func (that RotationInterval) start(t time.Time) time.Time {
+	switch that {
+	case RotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// next returns beginning of the period, that follows the time.
+func (that RotationInterval) next(t time.Time) time.Time {
+	switch that {
+	case RotateHourly:
+		return that.start(t).Add(time.Hour)
+	case RotateDaily:
+		return that.start(t).AddDate(0, 0, 1)
+	default:
+		return time.Time{}
+	}
+}
+
+// Compressor packs backups of the log.
+type Compressor interface {
+	// Extension is appended to the name of the packed backup.
+	Extension() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+type GzipCompressor struct {
+	Level int
+}
+
+func (that GzipCompressor) Extension() string {
+	return ".gz"
+}
+
+func (that GzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	level := that.Level
+	if level == 0 {
+		level = gzip.BestCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+func (l *Logger) compressor() Compressor {
+	if l.Compressor == nil {
+		return GzipCompressor{}
+	}
+	return l.Compressor
+}
+
+// archive packs the backup and removes the stale backups in the background.
+func (l *Logger) archive(name string) {
+	l.archives.Add(1)
+	go func() {
+		defer l.archives.Done()
+
+		l.archiveMu.Lock()
+		defer l.archiveMu.Unlock()
+
+		if err := l.compress(name); err != nil {
+			l.reportError(err)
+		}
+		if err := l.cleanup(); err != nil {
+			l.reportError(err)
+		}
+	}()
+}
+
+// compress streams the backup into the packed file and removes the original.
+// The packed file gets its name only when it is complete, so the broken file is never taken for backup.
+func (l *Logger) compress(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("can't open backup: %w", err)
+	}
+	defer src.Close()
+
+	target := name + l.compressor().Extension()
+	tmp := target + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("can't create packed backup: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	w, err := l.compressor().NewWriter(dst)
+	if err != nil {
+		return fmt.Errorf("can't create compressor: %w", err)
+	}
+	if _, err = io.Copy(w, src); err != nil {
+		return fmt.Errorf("can't compress backup: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("can't compress backup: %w", err)
+	}
+	if err = dst.Close(); err != nil {
+		return fmt.Errorf("can't close packed backup: %w", err)
+	}
+	if err = os.Rename(tmp, target); err != nil {
+		return fmt.Errorf("can't rename packed backup: %w", err)
+	}
+
+	_ = src.Close()
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("can't remove backup: %w", err)
+	}
+	return nil
+}
+
+// link points the symlink to the current file of the log.
+func (l *Logger) link() error {
+	if l.Symlink == "" {
+		return nil
+	}
+
+	target, err := filepath.Abs(l.fileName())
+	if err != nil {
+		return fmt.Errorf("can't resolve log file path: %w", err)
+	}
+
+	tmp := l.Symlink + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("can't create symlink: %w", err)
+	}
+	if err := os.Rename(tmp, l.Symlink); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("can't replace symlink: %w", err)
+	}
+	return nil
+}
+
+func (l *Logger) reportError(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "log rotation: %v\n", err)
+}
+
+// Reopen closes and opens the file of the log again.
+// It is used after the file was moved by the external tool, like logrotate.
+func (l *Logger) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.close(); err != nil {
+		return err
+	}
+	return l.openExistingOrNew(0)
+}
+
+// ReopenOn reopens the file on each of the signals (SIGHUP by default) until stop is called.
+func (l *Logger) ReopenOn(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := l.Reopen(); err != nil {
+					l.reportError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
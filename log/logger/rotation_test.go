@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setCurrentTime(t *testing.T, now *time.Time) {
+	prev := currentTime
+	currentTime = func() time.Time {
+		return *now
+	}
+	t.Cleanup(func() {
+		currentTime = prev
+	})
+}
+
+func readPacked(t *testing.T, name string) string {
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestLogger_RotateHourly(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 59, 0, 0, time.UTC)
+	setCurrentTime(t, &now)
+
+	dir := t.TempDir()
+	l := &Logger{
+		FileName: filepath.Join(dir, "app.log"),
+		Rotation: RotateHourly,
+		Symlink:  filepath.Join(dir, "current.log"),
+	}
+
+	_, err := l.Write([]byte("first\n"))
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	_, err = l.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	current, err := os.ReadFile(filepath.Join(dir, "current.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(current))
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "first\n", readPacked(t, backups[0]))
+
+	raw, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.Empty(t, raw)
+}
+
+func TestLogger_RotateStaleFileOnOpen(t *testing.T) {
+	now := time.Now().UTC()
+	setCurrentTime(t, &now)
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(name, []byte("yesterday\n"), 0644))
+	yesterday := now.AddDate(0, 0, -1)
+	require.NoError(t, os.Chtimes(name, yesterday, yesterday))
+
+	l := &Logger{FileName: name, Rotation: RotateDaily}
+	_, err := l.Write([]byte("today\n"))
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	current, err := os.ReadFile(name)
+	require.NoError(t, err)
+	assert.Equal(t, "today\n", string(current))
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "yesterday\n", readPacked(t, backups[0]))
+}
+
+func TestLogger_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	l := &Logger{FileName: name}
+
+	_, err := l.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(name, name+".1"))
+	require.NoError(t, l.Reopen())
+	_, err = l.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	moved, err := os.ReadFile(name + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(moved))
+	current, err := os.ReadFile(name)
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(current))
+}
+
+type upperCompressor struct{}
+
+func (that upperCompressor) Extension() string {
+	return ".up"
+}
+
+func (that upperCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return &upperWriter{w: w}, nil
+}
+
+type upperWriter struct {
+	w io.Writer
+}
+
+func (that *upperWriter) Write(p []byte) (int, error) {
+	return that.w.Write([]byte(strings.ToUpper(string(p))))
+}
+
+func (that *upperWriter) Close() error {
+	return nil
+}
+
+func TestLogger_Compressor(t *testing.T) {
+	dir := t.TempDir()
+	l := &Logger{
+		FileName:   filepath.Join(dir, "app.log"),
+		MaxBackups: 1,
+		Compressor: upperCompressor{},
+	}
+
+	_, err := l.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, l.Rotate())
+	require.NoError(t, l.Close())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log.up"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	data, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "FIRST\n", string(data))
+}
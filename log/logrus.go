@@ -95,3 +95,21 @@ func NewTemplateFormatter(purifier logrus.Purifier) *logrus.TemplateFormatter {
 		Purifier:        purifier,
 	}
 }
+
+func NewLogfmtFormatter(purifier logrus.Purifier) *logrus.LogfmtFormatter {
+	return &logrus.LogfmtFormatter{
+		Purifier: purifier,
+	}
+}
+
+func NewECSFormatter(purifier logrus.Purifier) *logrus.ECSFormatter {
+	return &logrus.ECSFormatter{
+		Purifier: purifier,
+	}
+}
+
+func NewGELFFormatter(purifier logrus.Purifier) *logrus.GELFFormatter {
+	return &logrus.GELFFormatter{
+		Purifier: purifier,
+	}
+}
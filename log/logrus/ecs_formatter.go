@@ -0,0 +1,113 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+const ecsVersion = "1.6.0"
+
+// ecsKeys maps the custom keys of the entry to the keys of the Elastic Common Schema.
+// The schema has no generic fields for the method and the subject, so they become labels.
+var ecsKeys = []struct {
+	key fieldKey
+	ecs string
+}{
+	{key: FieldKeyTraceID, ecs: "trace.id"},
+	{key: FieldKeySpanID, ecs: "span.id"},
+	{key: FieldKeyEntity, ecs: "event.module"},
+	{key: FieldKeyAction, ecs: "event.action"},
+	{key: FieldKeyMethod, ecs: "labels.method"},
+	{key: FieldKeySubject, ecs: "labels.subject"},
+}
+
+// ECSFormatter formats logs into Elastic Common Schema JSON.
+// The custom keys of the entry are mapped to the schema, the rest of the fields are kept as is.
+type ECSFormatter struct {
+	Purifier Purifier
+
+	// DisableHTMLEscape allows disabling html escaping in output
+	DisableHTMLEscape bool
+
+	// FieldMap allows users to customize the names of keys of the custom fields of the entry.
+	FieldMap FieldMap
+
+	// CallerPrettyfier can be set by the user to modify the content
+	// of the function and file keys in the json data when ReportCaller is
+	// activated. If any of the returned value is the empty string the
+	// corresponding key will be removed from json fields.
+	CallerPrettyfier func(*runtime.Frame) (function string, file string)
+
+	// ErrorChain enables rendering of the chain of the logged error with the stacks of its errors.
+	ErrorChain bool
+}
+
+// Format renders a single log entry
+func (f *ECSFormatter) Format(entry *Entry) ([]byte, error) {
+	dataKey := f.FieldMap.resolve(FieldKeyData)
+	data := purifyFields(f.Purifier, dataKey, entry.Data)
+	if f.ErrorChain {
+		prefixErrorChainClash(data, f.FieldMap)
+		appendErrorChain(data, entry.Data, f.FieldMap, f.Purifier)
+	}
+
+	for _, k := range []string{"@timestamp", "log.level", "message", "ecs.version"} {
+		if v, ok := data[k]; ok {
+			data["fields."+k] = v
+			delete(data, k)
+		}
+	}
+
+	for _, m := range ecsKeys {
+		k := f.FieldMap.resolve(m.key)
+		if v, ok := data[k]; ok {
+			delete(data, k)
+			data[m.ecs] = v
+		}
+	}
+	errorKey := f.FieldMap.resolve(fieldKey(ErrorKey))
+	if v, ok := data[errorKey]; ok {
+		delete(data, errorKey)
+		data["error.message"] = v
+	}
+
+	data["@timestamp"] = entry.Time.UTC().Format(time.RFC3339Nano)
+	data["log.level"] = entry.Level.String()
+	data["message"] = purifyMessage(f.Purifier, entry.Message)
+	data["ecs.version"] = ecsVersion
+	if entry.err != "" {
+		data[f.FieldMap.resolve(FieldKeyLogrusError)] = entry.err
+	}
+	if entry.HasCaller() {
+		funcVal := entry.Caller.Function
+		fileVal := entry.Caller.File
+		if f.CallerPrettyfier != nil {
+			funcVal, fileVal = f.CallerPrettyfier(entry.Caller)
+		}
+		if funcVal != "" {
+			data["log.origin.function"] = funcVal
+		}
+		if fileVal != "" {
+			data["log.origin.file.name"] = fileVal
+			data["log.origin.file.line"] = entry.Caller.Line
+		}
+	}
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(!f.DisableHTMLEscape)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+	}
+
+	return b.Bytes(), nil
+}
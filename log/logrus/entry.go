@@ -291,7 +291,12 @@ func (entry *Entry) write() {
 		fmt.Fprintf(os.Stderr, "Failed to obtain reader, %v\n", err)
 		return
 	}
-	if _, err := entry.Logger.Out.Write(serialized); err != nil {
+	if w, ok := entry.Logger.Out.(LevelWriter); ok {
+		_, err = w.WriteLevel(entry.Level, serialized)
+	} else {
+		_, err = entry.Logger.Out.Write(serialized)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
 	}
 }
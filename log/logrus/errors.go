@@ -0,0 +1,214 @@
+package logrus
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	maximumStackDepth = 32
+	maximumErrorChain = 32
+)
+
+var (
+	loggingPackage     string
+	loggingPackageOnce sync.Once
+)
+
+// Frame is the single call of the stack.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func (that Frame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", that.Function, that.File, that.Line)
+}
+
+// Stack is the stack of calls from the innermost.
+type Stack []Frame
+
+func (that Stack) String() string {
+	frames := make([]string, len(that))
+	for i, frame := range that {
+		frames[i] = frame.String()
+	}
+	return strings.Join(frames, ", ")
+}
+
+// StackTracer is implemented by the errors, that know the stack of their creation.
+type StackTracer interface {
+	StackTrace() Stack
+}
+
+// Callers returns stack of the caller.
+// The frames of the logging packages on the top of the stack are skipped,
+// so the stack starts from the code, that creates or logs the error.
+func Callers() Stack {
+	loggingPackageOnce.Do(func() {
+		fn := runtime.FuncForPC(reflect.ValueOf(Callers).Pointer()).Name()
+		loggingPackage = path.Dir(getPackageName(fn))
+	})
+
+	pcs := make([]uintptr, maximumStackDepth+maximumCallerDepth)
+	depth := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:depth])
+
+	stack := make(Stack, 0, depth)
+	skip := true
+	for f, again := frames.Next(); again; f, again = frames.Next() {
+		if skip && isLoggingPackage(getPackageName(f.Function)) {
+			continue
+		}
+		skip = false
+
+		stack = append(stack, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if len(stack) == maximumStackDepth {
+			break
+		}
+	}
+	return stack
+}
+
+func isLoggingPackage(pkg string) bool {
+	return pkg == loggingPackage || strings.HasPrefix(pkg, loggingPackage+"/")
+}
+
+type stackError struct {
+	error
+	stack Stack
+}
+
+func (that *stackError) Unwrap() error {
+	return that.error
+}
+
+func (that *stackError) StackTrace() Stack {
+	return that.stack
+}
+
+// WithStack annotates the error with the stack of the caller.
+// The error is returned unchanged, if it is nil or already has the stack.
+func WithStack(err error) error {
+	if err == nil || hasStack(err) {
+		return err
+	}
+
+	return &stackError{error: err, stack: Callers()}
+}
+
+// hasStack returns true, if any error of the chain has the stack.
+func hasStack(err error) bool {
+	for _, info := range UnwrapError(err) {
+		if len(info.Stack) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorInfo describes the single error of the chain.
+type ErrorInfo struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	// Depth is the level of nesting of the error, the logged error has zero depth.
+	Depth int   `json:"depth,omitempty"`
+	Stack Stack `json:"stack,omitempty"`
+}
+
+func (that ErrorInfo) String() string {
+	s := fmt.Sprintf("%s%s (%s)", strings.Repeat("> ", that.Depth), that.Message, that.Type)
+	if len(that.Stack) != 0 {
+		s += " at " + that.Stack.String()
+	}
+	return s
+}
+
+// ErrorChain is the list of the errors, that are wrapped or joined by the logged error, in depth first order.
+type ErrorChain []ErrorInfo
+
+func (that ErrorChain) String() string {
+	infos := make([]string, len(that))
+	for i, info := range that {
+		infos[i] = info.String()
+	}
+	return strings.Join(infos, "; ")
+}
+
+// isDetailed returns true, if the chain tells more than the message of the error.
+func (that ErrorChain) isDetailed() bool {
+	return len(that) > 1 || (len(that) == 1 && len(that[0].Stack) != 0)
+}
+
+// UnwrapError returns the chain of the error.
+// Errors are unwrapped with Unwrap() error and Unwrap() []error methods.
+// The stack of the annotated error is attached to the error it annotates.
+func UnwrapError(err error) ErrorChain {
+	var chain ErrorChain
+	unwrapError(&chain, err, 0, nil)
+	return chain
+}
+
+func unwrapError(chain *ErrorChain, err error, depth int, stack Stack) {
+	if err == nil || len(*chain) >= maximumErrorChain {
+		return
+	}
+
+	if e, ok := err.(*stackError); ok {
+		if stack == nil {
+			stack = e.stack
+		}
+		unwrapError(chain, e.error, depth, stack)
+		return
+	}
+
+	if stack == nil {
+		if e, ok := err.(StackTracer); ok {
+			stack = e.StackTrace()
+		}
+	}
+
+	*chain = append(*chain, ErrorInfo{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+		Depth:   depth,
+		Stack:   stack,
+	})
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			unwrapError(chain, inner, depth+1, nil)
+		}
+	default:
+		unwrapError(chain, errors.Unwrap(err), depth+1, nil)
+	}
+}
+
+// appendErrorChain adds the chain of the error of the entry to the data, if the chain tells more than the error.
+// Messages of the chain are purified as the error field, so the denied error is not leaked through the chain.
+func appendErrorChain(data Fields, fields Fields, fieldMap FieldMap, purifier Purifier) {
+	err, ok := fields[ErrorKey].(error)
+	if !ok {
+		return
+	}
+
+	chain := UnwrapError(err)
+	if !chain.isDetailed() {
+		return
+	}
+
+	if fp, ok := purifier.(FieldPurifier); ok {
+		for i := range chain {
+			chain[i].Message = fp.PurifyField(ErrorKey, chain[i].Message)
+		}
+	}
+
+	data[fieldMap.resolve(FieldKeyErrors)] = chain
+}
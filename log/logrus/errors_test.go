@@ -0,0 +1,179 @@
+package logrus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+type joinedErrors []error
+
+func (that joinedErrors) Error() string {
+	messages := make([]string, len(that))
+	for i, err := range that {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (that joinedErrors) Unwrap() []error {
+	return that
+}
+
+type tracedError struct {
+	stack Stack
+}
+
+func (that *tracedError) Error() string {
+	return "traced"
+}
+
+func (that *tracedError) StackTrace() Stack {
+	return that.stack
+}
+
+func TestUnwrapError(t *testing.T) {
+	errBase := errors.New("base")
+	errOther := errors.New("other")
+	err := fmt.Errorf("save: %w", joinedErrors{fmt.Errorf("load: %w", errBase), errOther})
+
+	chain := UnwrapError(err)
+	require.Len(t, chain, 5)
+	assert.Equal(t, ErrorInfo{Message: "save: load: base\nother", Type: "*fmt.wrapError"}, chain[0])
+	assert.Equal(t, ErrorInfo{Message: "load: base\nother", Type: "logrus.joinedErrors", Depth: 1}, chain[1])
+	assert.Equal(t, ErrorInfo{Message: "load: base", Type: "*fmt.wrapError", Depth: 2}, chain[2])
+	assert.Equal(t, ErrorInfo{Message: "base", Type: "*errors.errorString", Depth: 3}, chain[3])
+	assert.Equal(t, ErrorInfo{Message: "other", Type: "*errors.errorString", Depth: 2}, chain[4])
+
+	assert.Nil(t, UnwrapError(nil))
+
+	stack := Stack{{Function: "main.run", File: "main.go", Line: 10}}
+	chain = UnwrapError(fmt.Errorf("run: %w", &tracedError{stack: stack}))
+	require.Len(t, chain, 2)
+	assert.Equal(t, stack, chain[1].Stack)
+	assert.Equal(t, "run: traced (*fmt.wrapError); > traced (*logrus.tracedError) at main.run (main.go:10)", chain.String())
+}
+
+func TestWithStack(t *testing.T) {
+	assert.Nil(t, WithStack(nil))
+
+	errBase := errors.New("base")
+	err := WithStack(errBase)
+	assert.Equal(t, "base", err.Error())
+	assert.True(t, errors.Is(err, errBase))
+	assert.Same(t, err, WithStack(err))
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	assert.Same(t, wrapped, WithStack(wrapped))
+
+	chain := UnwrapError(wrapped)
+	require.Len(t, chain, 2)
+	assert.Empty(t, chain[0].Stack)
+	assert.Equal(t, "base", chain[1].Message)
+	assert.Equal(t, "*errors.errorString", chain[1].Type)
+	require.NotEmpty(t, chain[1].Stack)
+
+	// frames of the logging packages are skipped, including the tests of this package
+	for _, frame := range chain[1].Stack {
+		assert.False(t, isLoggingPackage(getPackageName(frame.Function)), frame.Function)
+	}
+	assert.Equal(t, "testing.tRunner", chain[1].Stack[0].Function)
+}
+
+type errorPurifier struct{}
+
+func (that *errorPurifier) Purify(original, derivative string) string {
+	return derivative
+}
+
+func (that *errorPurifier) PurifyField(key, value string) string {
+	if key == ErrorKey {
+		return strings.ReplaceAll(value, "secret", "***")
+	}
+	return value
+}
+
+func newErrorEntry(err error) *Entry {
+	return &Entry{
+		Data:    Fields{ErrorKey: err},
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   ErrorLevel,
+		Message: "failed",
+	}
+}
+
+func TestFormatters_ErrorChain(t *testing.T) {
+	err := fmt.Errorf("save: %w", errors.New("password secret"))
+
+	data, fmtErr := (&JSONFormatter{DisableTimestamp: true, ErrorChain: true}).Format(newErrorEntry(err))
+	require.NoError(t, fmtErr)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "save: password secret", doc[ErrorKey])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"message": "save: password secret", "type": "*fmt.wrapError"},
+		map[string]interface{}{"message": "password secret", "type": "*errors.errorString", "depth": float64(1)},
+	}, doc[FieldKeyErrors])
+
+	data, fmtErr = (&TextFormatter{DisableTimestamp: true, DisableColors: true, ErrorChain: true}).Format(newErrorEntry(err))
+	require.NoError(t, fmtErr)
+	assert.Equal(
+		t,
+		`level=error msg=failed error="save: password secret" `+
+			`errors="save: password secret (*fmt.wrapError); > password secret (*errors.errorString)"`+"\n",
+		string(data),
+	)
+
+	data, fmtErr = (&LogfmtFormatter{DisableTimestamp: true, Purifier: &errorPurifier{}, ErrorChain: true}).Format(newErrorEntry(err))
+	require.NoError(t, fmtErr)
+	assert.Equal(
+		t,
+		`level=error msg=failed error="save: password ***" `+
+			`errors="save: password *** (*fmt.wrapError); > password *** (*errors.errorString)"`+"\n",
+		string(data),
+	)
+
+	data, fmtErr = (&GELFFormatter{Host: "h", ErrorChain: true}).Format(newErrorEntry(err))
+	require.NoError(t, fmtErr)
+	doc = nil
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "save: password secret (*fmt.wrapError); > password secret (*errors.errorString)", doc["_errors"])
+
+	data, fmtErr = (&TemplateFormatter{DisableTimestamp: true, Purifier: &errorPurifier{}, ErrorChain: true}).Format(newErrorEntry(err))
+	require.NoError(t, fmtErr)
+	assert.NotContains(t, string(data), "secret")
+	assert.Contains(t, string(data), "password *** (*errors.errorString)")
+
+	// the chain of the plain error tells nothing more
+	data, fmtErr = (&JSONFormatter{DisableTimestamp: true, ErrorChain: true}).Format(newErrorEntry(errors.New("plain")))
+	require.NoError(t, fmtErr)
+	assert.Equal(t, `{"error":"plain","level":"error","msg":"failed"}`+"\n", string(data))
+
+	// the chain is rendered only on demand
+	data, fmtErr = (&JSONFormatter{DisableTimestamp: true}).Format(newErrorEntry(err))
+	require.NoError(t, fmtErr)
+	assert.Equal(t, `{"error":"save: password secret","level":"error","msg":"failed"}`+"\n", string(data))
+}
+
+func TestFormatters_ErrorChainClash(t *testing.T) {
+	entry := newErrorEntry(fmt.Errorf("save: %w", errors.New("failed")))
+	entry.Data[FieldKeyErrors] = 2
+
+	data, err := (&LogfmtFormatter{DisableTimestamp: true, ErrorChain: true}).Format(entry)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`level=error msg=failed error="save: failed" `+
+			`errors="save: failed (*fmt.wrapError); > failed (*errors.errorString)" fields.errors=2`+"\n",
+		string(data),
+	)
+
+	data, err = (&LogfmtFormatter{DisableTimestamp: true}).Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, `level=error msg=failed error="save: failed" errors=2`+"\n", string(data))
+}
@@ -14,9 +14,11 @@ const (
 	FieldKeyEntity         = "entity"
 	FieldKeyAction         = "action"
 	FieldKeyTraceID        = "trace_id"
+	FieldKeySpanID         = "span_id"
 	FieldKeyMethod         = "method"
 	FieldKeySubject        = "subject"
 	FieldKeyData           = "data"
+	FieldKeyErrors         = "errors"
 )
 
 // The Formatter interface is used to implement a custom Formatter. It takes an
@@ -45,7 +47,7 @@ type Formatter interface {
 //
 // It's not exported because it's still using Data in an opinionated way. It's to
 // avoid code duplication between the two default formatters.
-func prefixFieldClashes(data Fields, fieldMap FieldMap, reportCaller bool) {
+func prefixFieldClashes(data Fields, fieldMap FieldMap, reportCaller bool, errorChain bool) {
 	timeKey := fieldMap.resolve(FieldKeyTime)
 	if t, ok := data[timeKey]; ok {
 		data["fields."+timeKey] = t
@@ -99,4 +101,17 @@ func prefixFieldClashes(data Fields, fieldMap FieldMap, reportCaller bool) {
 			data["fields."+fileKey] = l
 		}
 	}
+
+	// If errorChain is not set, 'errors' will not conflict.
+	if errorChain {
+		prefixErrorChainClash(data, fieldMap)
+	}
+}
+
+func prefixErrorChainClash(data Fields, fieldMap FieldMap) {
+	errorsKey := fieldMap.resolve(FieldKeyErrors)
+	if l, ok := data[errorsKey]; ok {
+		data["fields."+errorsKey] = l
+		delete(data, errorsKey)
+	}
 }
@@ -0,0 +1,151 @@
+package logrus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+type secretPurifier struct{}
+
+func (that *secretPurifier) Purify(original, derivative string) string {
+	return strings.ReplaceAll(derivative, "secret", "***")
+}
+
+func (that *secretPurifier) PurifyField(key, value string) string {
+	if key == "password" {
+		return "***"
+	}
+	return value
+}
+
+func newFormatterEntry() *Entry {
+	return &Entry{
+		Data: Fields{
+			"ent":          "USER",
+			FieldKeyAction: ">>",
+			"password":     "qwerty",
+			FieldKeyData:   "body with secret",
+			ErrorKey:       errors.New("failure"),
+			"count":        2,
+		},
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC),
+		Level:   WarnLevel,
+		Message: "hello secret",
+		Context: context.Background(),
+	}
+}
+
+func TestLogfmtFormatter_Format(t *testing.T) {
+	f := &LogfmtFormatter{
+		Purifier: &secretPurifier{},
+		FieldMap: FieldMap{FieldKeyEntity: "ent", FieldKeyMsg: "message"},
+	}
+
+	data, err := f.Format(newFormatterEntry())
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`time=2024-01-02T03:04:05Z level=warning message="hello ***" ent=USER action=>> `+
+			`count=2 data="body with ***" error=failure password=***`+"\n",
+		string(data),
+	)
+}
+
+func TestECSFormatter_Format(t *testing.T) {
+	f := &ECSFormatter{
+		Purifier: &secretPurifier{},
+		FieldMap: FieldMap{FieldKeyEntity: "ent"},
+	}
+
+	data, err := f.Format(newFormatterEntry())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"@timestamp": "2024-01-02T03:04:05.6Z",
+		"log.level": "warning",
+		"message": "hello ***",
+		"ecs.version": "1.6.0",
+		"event.module": "USER",
+		"event.action": ">>",
+		"error.message": "failure",
+		"password": "***",
+		"data": "body with ***",
+		"count": 2
+	}`, string(data))
+}
+
+func TestECSFormatter_FieldMap(t *testing.T) {
+	f := &ECSFormatter{
+		FieldMap: FieldMap{fieldKey(ErrorKey): "err", FieldKeySubject: "subj"},
+	}
+
+	data, err := f.Format(&Entry{
+		Data: Fields{
+			"err":          errors.New("failure"),
+			FieldKeyMethod: "GET",
+			"subj":         "/orders",
+		},
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   InfoLevel,
+		Message: "hello",
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"@timestamp": "2024-01-02T03:04:05Z",
+		"log.level": "info",
+		"message": "hello",
+		"ecs.version": "1.6.0",
+		"error.message": "failure",
+		"labels.method": "GET",
+		"labels.subject": "/orders"
+	}`, string(data))
+}
+
+func TestGELFFormatter_Format(t *testing.T) {
+	f := &GELFFormatter{
+		Purifier:      &secretPurifier{},
+		Host:          "example.org",
+		NullDelimiter: true,
+		FieldMap:      FieldMap{FieldKeyEntity: "ent"},
+	}
+
+	data, err := f.Format(newFormatterEntry())
+	require.NoError(t, err)
+	require.Equal(t, byte(0), data[len(data)-1])
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(data[:len(data)-1], &msg))
+	assert.Equal(t, map[string]interface{}{
+		"version":       "1.1",
+		"host":          "example.org",
+		"short_message": "hello ***",
+		"full_message":  "body with ***",
+		"timestamp":     1704164645.6,
+		"level":         float64(4),
+		"_ent":          "USER",
+		"_action":       ">>",
+		"_error":        "failure",
+		"_password":     "***",
+		"_count":        float64(2),
+	}, msg)
+}
+
+func TestGelfValue(t *testing.T) {
+	assert.Equal(t, "text", gelfValue("text"))
+	assert.Equal(t, 2, gelfValue(2))
+	assert.Equal(t, 1, gelfValue(true))
+	assert.Equal(t, 0, gelfValue(false))
+	assert.Equal(t, `{"a":1}`, gelfValue(map[string]int{"a": 1}))
+	assert.Equal(t, `["a","b"]`, gelfValue([]string{"a", "b"}))
+	assert.Equal(t, "2024-01-02T03:04:05Z", gelfValue(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+}
+
+func TestGelfKey(t *testing.T) {
+	assert.Equal(t, "__id", gelfKey("id"))
+	assert.Equal(t, "_http.status_code", gelfKey("http.status code"))
+}
@@ -0,0 +1,159 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+const gelfVersion = "1.1"
+
+// gelfLevels maps levels to the syslog severities.
+var gelfLevels = map[Level]int{
+	PanicLevel: 1,
+	FatalLevel: 2,
+	ErrorLevel: 3,
+	WarnLevel:  4,
+	InfoLevel:  6,
+	DebugLevel: 7,
+	TraceLevel: 7,
+}
+
+// GELFFormatter formats logs into Graylog Extended Log Format messages.
+// The data field of the entry becomes the full message, the other fields become additional fields.
+type GELFFormatter struct {
+	Purifier Purifier
+
+	// Host is the source of the messages. Default is name of the host.
+	Host string
+
+	// NullDelimiter terminates messages with the null byte instead of the new line, as GELF TCP input requires.
+	NullDelimiter bool
+
+	// FieldMap allows users to customize the names of keys of the custom fields of the entry.
+	FieldMap FieldMap
+
+	// CallerPrettyfier can be set by the user to modify the content
+	// of the function and file keys in the json data when ReportCaller is
+	// activated. If any of the returned value is the empty string the
+	// corresponding key will be removed from json fields.
+	CallerPrettyfier func(*runtime.Frame) (function string, file string)
+
+	// ErrorChain enables rendering of the chain of the logged error with the stacks of its errors.
+	ErrorChain bool
+}
+
+// Format renders a single log entry
+func (f *GELFFormatter) Format(entry *Entry) ([]byte, error) {
+	dataKey := f.FieldMap.resolve(FieldKeyData)
+	fields := purifyFields(f.Purifier, dataKey, entry.Data)
+	if f.ErrorChain {
+		prefixErrorChainClash(fields, f.FieldMap)
+		appendErrorChain(fields, entry.Data, f.FieldMap, f.Purifier)
+	}
+
+	host := f.Host
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	data := make(Fields, len(fields)+8)
+	data["version"] = gelfVersion
+	data["host"] = host
+	data["short_message"] = purifyMessage(f.Purifier, entry.Message)
+	data["timestamp"] = float64(entry.Time.UnixNano()/int64(1000)) / 1e6
+	data["level"] = gelfLevels[entry.Level]
+
+	if v, ok := fields[dataKey]; ok {
+		delete(fields, dataKey)
+		data["full_message"] = fmt.Sprint(v)
+	}
+	for k, v := range fields {
+		data[gelfKey(k)] = gelfValue(v)
+	}
+	if entry.err != "" {
+		data[gelfKey(f.FieldMap.resolve(FieldKeyLogrusError))] = entry.err
+	}
+	if entry.HasCaller() {
+		funcVal := entry.Caller.Function
+		fileVal := fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+		if f.CallerPrettyfier != nil {
+			funcVal, fileVal = f.CallerPrettyfier(entry.Caller)
+		}
+		if funcVal != "" {
+			data[gelfKey(f.FieldMap.resolve(FieldKeyFunc))] = funcVal
+		}
+		if fileVal != "" {
+			data[gelfKey(f.FieldMap.resolve(FieldKeyFile))] = fileVal
+		}
+	}
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	encoder := json.NewEncoder(b)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+	}
+	if f.NullDelimiter {
+		b.Truncate(b.Len() - 1)
+		b.WriteByte(0)
+	}
+
+	return b.Bytes(), nil
+}
+
+// gelfValue returns value of the additional field, that may be only a string or a number.
+// Booleans become 1 and 0, other values are rendered as JSON strings.
+func gelfValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string, json.Number,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case ErrorChain:
+		return v.String()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		// values like time.Time are marshaled into JSON strings
+		return s
+	}
+	return string(data)
+}
+
+// gelfKey returns name of the additional field.
+// Names are prefixed with the underscore and may contain only letters, digits, underscores, dashes and dots.
+// The reserved name "_id" is escaped.
+func gelfKey(key string) string {
+	key = "_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, key)
+
+	if key == "_id" {
+		return "__id"
+	}
+	return key
+}
@@ -57,6 +57,9 @@ type JSONFormatter struct {
 
 	// PrettyPrint will indent all json logs
 	PrettyPrint bool
+
+	// ErrorChain enables rendering of the chain of the logged error with the stacks of its errors.
+	ErrorChain bool
 }
 
 // Format renders a single log entry
@@ -79,7 +82,10 @@ func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
 		data = newData
 	}
 
-	prefixFieldClashes(data, f.FieldMap, entry.HasCaller())
+	prefixFieldClashes(data, f.FieldMap, entry.HasCaller(), f.ErrorChain)
+	if f.ErrorChain {
+		appendErrorChain(data, entry.Data, f.FieldMap, nil)
+	}
 
 	timestampFormat := f.TimestampFormat
 	if timestampFormat == "" {
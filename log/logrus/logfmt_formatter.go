@@ -0,0 +1,147 @@
+package logrus
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LogfmtFormatter formats logs into logfmt key=value pairs
+type LogfmtFormatter struct {
+	Purifier Purifier
+
+	// DisableTimestamp allows disabling automatic timestamps in output
+	DisableTimestamp bool
+
+	// TimestampFormat to use for display when a full timestamp is printed.
+	TimestampFormat string
+
+	// The fields are sorted by default for a consistent output.
+	DisableSorting bool
+
+	// FieldMap allows users to customize the names of keys for default fields.
+	FieldMap FieldMap
+
+	// CallerPrettyfier can be set by the user to modify the content
+	// of the function and file keys in the data when ReportCaller is
+	// activated. If any of the returned value is the empty string the
+	// corresponding key will be removed from fields.
+	CallerPrettyfier func(*runtime.Frame) (function string, file string)
+
+	// ErrorChain enables rendering of the chain of the logged error with the stacks of its errors.
+	ErrorChain bool
+}
+
+// Format renders a single log entry
+func (f *LogfmtFormatter) Format(entry *Entry) ([]byte, error) {
+	dataKey := f.FieldMap.resolve(FieldKeyData)
+	data := purifyFields(f.Purifier, dataKey, entry.Data)
+	prefixFieldClashes(data, f.FieldMap, entry.HasCaller(), f.ErrorChain)
+	if f.ErrorChain {
+		appendErrorChain(data, entry.Data, f.FieldMap, f.Purifier)
+	}
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	timestampFormat := f.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = defaultTimestampFormat
+	}
+
+	if !f.DisableTimestamp {
+		f.appendKeyValue(b, f.FieldMap.resolve(FieldKeyTime), entry.Time.Format(timestampFormat))
+	}
+	f.appendKeyValue(b, f.FieldMap.resolve(FieldKeyLevel), entry.Level.String())
+	f.appendKeyValue(b, f.FieldMap.resolve(FieldKeyMsg), purifyMessage(f.Purifier, entry.Message))
+	if entry.err != "" {
+		f.appendKeyValue(b, f.FieldMap.resolve(FieldKeyLogrusError), entry.err)
+	}
+	if entry.HasCaller() {
+		funcVal := entry.Caller.Function
+		fileVal := fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+		if f.CallerPrettyfier != nil {
+			funcVal, fileVal = f.CallerPrettyfier(entry.Caller)
+		}
+		if funcVal != "" {
+			f.appendKeyValue(b, f.FieldMap.resolve(FieldKeyFunc), funcVal)
+		}
+		if fileVal != "" {
+			f.appendKeyValue(b, f.FieldMap.resolve(FieldKeyFile), fileVal)
+		}
+	}
+
+	// custom keys of the entry go first, because they identify the event
+	for _, key := range []fieldKey{FieldKeyTraceID, FieldKeySpanID, FieldKeyEntity, FieldKeyAction, FieldKeyMethod, FieldKeySubject} {
+		k := f.FieldMap.resolve(key)
+		if v, ok := data[k]; ok {
+			f.appendKeyValue(b, k, v)
+			delete(data, k)
+		}
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	if !f.DisableSorting {
+		sort.Strings(keys)
+	}
+	for _, k := range keys {
+		f.appendKeyValue(b, k, data[k])
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+func (f *LogfmtFormatter) appendKeyValue(b *bytes.Buffer, key string, value interface{}) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+
+	stringVal, ok := value.(string)
+	if !ok {
+		stringVal = fmt.Sprint(value)
+	}
+	if logfmtNeedsQuoting(stringVal) {
+		b.WriteString(strconv.Quote(stringVal))
+	} else {
+		b.WriteString(stringVal)
+	}
+}
+
+// logfmtKey replaces characters, that are not allowed in the keys.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtNeedsQuoting(text string) bool {
+	if text == "" {
+		return true
+	}
+	for _, r := range text {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -6,3 +6,41 @@ type DummyPurifier struct {
 func (f *DummyPurifier) Purify(original, derivative string) string {
 	return derivative
 }
+
+// purifyMessage purifies the message of the entry.
+func purifyMessage(purifier Purifier, msg string) string {
+	if purifier == nil {
+		return msg
+	}
+
+	return purifier.Purify(msg, msg)
+}
+
+// purifyFields returns copy of the fields with errors converted to strings.
+// The data field is purified as the message, the other string fields are purified by their names.
+func purifyFields(purifier Purifier, dataKey string, fields Fields) Fields {
+	fp, _ := purifier.(FieldPurifier)
+	data := make(Fields, len(fields))
+	for k, v := range fields {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case error:
+			s = v.Error()
+		default:
+			data[k] = v
+			continue
+		}
+
+		switch {
+		case k == dataKey:
+			s = purifyMessage(purifier, s)
+		case fp != nil:
+			s = fp.PurifyField(k, s)
+		}
+		data[k] = s
+	}
+
+	return data
+}
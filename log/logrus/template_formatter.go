@@ -15,6 +15,11 @@ type Purifier interface {
 	Purify(original, derivative string) string
 }
 
+// FieldPurifier is implemented by the purifiers, that purify values of the fields by their names.
+type FieldPurifier interface {
+	PurifyField(key, value string) string
+}
+
 // TemplateFormatter formats logs into text
 type TemplateFormatter struct {
 	Purifier Purifier
@@ -58,6 +63,9 @@ type TemplateFormatter struct {
 	// corresponding key will be removed from fields.
 	CallerPrettyfier func(*runtime.Frame) (function string, file string)
 
+	// ErrorChain enables rendering of the chain of the logged error with the stacks of its errors.
+	ErrorChain bool
+
 	// The max length of the level text, generated dynamically on init
 	levelTextMaxLength int
 
@@ -84,7 +92,10 @@ func (f *TemplateFormatter) Format(entry *Entry) ([]byte, error) {
 	for k, v := range entry.Data {
 		data[k] = v
 	}
-	prefixFieldClashes(data, f.FieldMap, entry.HasCaller())
+	prefixFieldClashes(data, f.FieldMap, entry.HasCaller(), f.ErrorChain)
+	if f.ErrorChain {
+		appendErrorChain(data, entry.Data, f.FieldMap, f.Purifier)
+	}
 	keys := make([]string, 0, len(data))
 	for k := range data {
 		keys = append(keys, k)
@@ -195,7 +206,7 @@ func (f *TemplateFormatter) Format(entry *Entry) ([]byte, error) {
 			value = data[key]
 		}
 
-		val := f.value2string(value)
+		val := f.purifyField(key, f.value2string(value))
 		if _, ok := systemFields[key]; ok {
 			params[key] = val
 		} else {
@@ -293,6 +304,14 @@ func (f *TemplateFormatter) purify(s string) string {
 	return f.Purifier.Purify(s, s)
 }
 
+func (f *TemplateFormatter) purifyField(key, value string) string {
+	if p, ok := f.Purifier.(FieldPurifier); ok {
+		return p.PurifyField(key, value)
+	}
+
+	return value
+}
+
 var funcMap = template.FuncMap{
 	"ToUpper": strings.ToUpper,
 }
@@ -95,6 +95,9 @@ type TextFormatter struct {
 	// corresponding key will be removed from fields.
 	CallerPrettyfier func(*runtime.Frame) (function string, file string)
 
+	// ErrorChain enables rendering of the chain of the logged error with the stacks of its errors.
+	ErrorChain bool
+
 	terminalInitOnce sync.Once
 
 	// The max length of the level text, generated dynamically on init
@@ -135,7 +138,10 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	for k, v := range entry.Data {
 		data[k] = v
 	}
-	prefixFieldClashes(data, f.FieldMap, entry.HasCaller())
+	prefixFieldClashes(data, f.FieldMap, entry.HasCaller(), f.ErrorChain)
+	if f.ErrorChain {
+		appendErrorChain(data, entry.Data, f.FieldMap, nil)
+	}
 	keys := make([]string, 0, len(data))
 	for k := range data {
 		keys = append(keys, k)
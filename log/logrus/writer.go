@@ -7,6 +7,13 @@ import (
 	"strings"
 )
 
+// LevelWriter is an output, that receives level of the serialized entry.
+// The logger prefers WriteLevel to Write, when the output implements it.
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level Level, p []byte) (n int, err error)
+}
+
 // Writer at INFO level. See WriterLevel for details.
 func (logger *Logger) Writer() *io.PipeWriter {
 	return logger.WriterLevel(InfoLevel)
@@ -137,6 +137,38 @@ func (that *LoggerMock) Panicln(ctx context.Context, args ...interface{}) {
 	that.Called(ctx, args)
 }
 
+func (that *LoggerMock) Enabled(ctx context.Context, level Level) bool {
+	return that.Called(ctx, level).Bool(0)
+}
+
+func (that *LoggerMock) Tracew(ctx context.Context, msg string, fields ...Field) {
+	that.Called(ctx, msg, fields)
+}
+
+func (that *LoggerMock) Debugw(ctx context.Context, msg string, fields ...Field) {
+	that.Called(ctx, msg, fields)
+}
+
+func (that *LoggerMock) Infow(ctx context.Context, msg string, fields ...Field) {
+	that.Called(ctx, msg, fields)
+}
+
+func (that *LoggerMock) Warningw(ctx context.Context, msg string, fields ...Field) {
+	that.Called(ctx, msg, fields)
+}
+
+func (that *LoggerMock) Errorw(ctx context.Context, msg string, fields ...Field) {
+	that.Called(ctx, msg, fields)
+}
+
+func (that *LoggerMock) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	that.Called(ctx, msg, fields)
+}
+
+func (that *LoggerMock) Panicw(ctx context.Context, msg string, fields ...Field) {
+	that.Called(ctx, msg, fields)
+}
+
 func NewLoggerMock() *LoggerMock {
 	return new(LoggerMock)
 }
@@ -241,6 +273,31 @@ func (that *DummyLogger) Fatalln(ctx context.Context, args ...interface{}) {
 func (that *DummyLogger) Panicln(ctx context.Context, args ...interface{}) {
 }
 
+func (that *DummyLogger) Enabled(ctx context.Context, level Level) bool {
+	return false
+}
+
+func (that *DummyLogger) Tracew(ctx context.Context, msg string, fields ...Field) {
+}
+
+func (that *DummyLogger) Debugw(ctx context.Context, msg string, fields ...Field) {
+}
+
+func (that *DummyLogger) Infow(ctx context.Context, msg string, fields ...Field) {
+}
+
+func (that *DummyLogger) Warningw(ctx context.Context, msg string, fields ...Field) {
+}
+
+func (that *DummyLogger) Errorw(ctx context.Context, msg string, fields ...Field) {
+}
+
+func (that *DummyLogger) Fatalw(ctx context.Context, msg string, fields ...Field) {
+}
+
+func (that *DummyLogger) Panicw(ctx context.Context, msg string, fields ...Field) {
+}
+
 func NewDummyLogger() *DummyLogger {
 	return new(DummyLogger)
 }
@@ -103,6 +103,48 @@ func Panicln(ctx context.Context, args ...interface{}) {
 	getLogger(ctx, SysLog).Panicln(ctx, args...)
 }
 
+func Tracew(ctx context.Context, msg string, fields ...Field) {
+	logFields(ctx, TraceLevel, msg, fields)
+}
+
+func Debugw(ctx context.Context, msg string, fields ...Field) {
+	logFields(ctx, DebugLevel, msg, fields)
+}
+
+func Infow(ctx context.Context, msg string, fields ...Field) {
+	logFields(ctx, InfoLevel, msg, fields)
+}
+
+func Warningw(ctx context.Context, msg string, fields ...Field) {
+	logFields(ctx, WarnLevel, msg, fields)
+}
+
+func Errorw(ctx context.Context, msg string, fields ...Field) {
+	logFields(ctx, ErrorLevel, msg, fields)
+}
+
+func Fatalw(ctx context.Context, msg string, fields ...Field) {
+	logFields(ctx, FatalLevel, msg, fields)
+}
+
+func Panicw(ctx context.Context, msg string, fields ...Field) {
+	logFields(ctx, PanicLevel, msg, fields)
+}
+
+// logFields passes copy of the fields to the logger, so the fields don't escape
+// and the call doesn't allocate, when the level is disabled.
+func logFields(ctx context.Context, level Level, msg string, fields []Field) {
+	l := getLogger(ctx, SysLog)
+	if !IsEnabled(ctx, l, level) {
+		return
+	}
+
+	fs := make([]Field, len(fields))
+	copy(fs, fields)
+
+	Logw(ctx, l, level, msg, fs...)
+}
+
 // deprecated functions
 func DoPrintln(ctx context.Context, v ...interface{}) {
 	if SysLog == nil {
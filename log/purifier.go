@@ -178,6 +178,10 @@ func (that *PNGPurifier) Purify(original, derivative string) string {
 	return that.next.Purify(original, derivative)
 }
 
+func (that *PNGPurifier) PurifyField(key, value string) string {
+	return purifyField(that.next, key, value)
+}
+
 type LenPurifier struct {
 	maxLen  int
 	storage ChunkStorage
@@ -204,6 +208,10 @@ func (that *LenPurifier) Purify(original, derivative string) string {
 	return that.storage.Save(derivative)
 }
 
+func (that *LenPurifier) PurifyField(key, value string) string {
+	return purifyField(that.next, key, value)
+}
+
 type MultilinePurifier struct {
 	next Purifier
 }
@@ -222,6 +230,10 @@ func (that *MultilinePurifier) Purify(original, derivative string) string {
 	return that.next.Purify(original, derivative)
 }
 
+func (that *MultilinePurifier) PurifyField(key, value string) string {
+	return purifyField(that.next, key, value)
+}
+
 func (that *MultilinePurifier) purify(s string) string {
 	if s2, ok := that.purifyAsJson(s); ok {
 		return s2
@@ -253,6 +265,15 @@ func (that *MultilinePurifier) purifyAsPlain(s string) string {
 	return strings.Join(lines, " ")
 }
 
+// purifyField passes the field to the next purifier of the chain, if it purifies fields.
+func purifyField(next Purifier, key, value string) string {
+	if p, ok := next.(FieldPurifier); ok {
+		return p.PurifyField(key, value)
+	}
+
+	return value
+}
+
 func isPNG(s string) bool {
 	return len(s) >= 4 && s[0] == 0x89 && s[1] == 0x50 && s[2] == 0x4E && s[3] == 0x47
 }
@@ -0,0 +1,290 @@
+package log
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/Adverax/core/json"
+	"regexp"
+	"strings"
+)
+
+const defaultRedactionMask = "[REDACTED]"
+
+// DefaultRedactedFields are names of the fields, that are redacted by default.
+var DefaultRedactedFields = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+	"authorization",
+	"cookie",
+}
+
+// RedactionPattern finds sensitive values in the text.
+// If the regexp has the group, only value of the first group is redacted.
+type RedactionPattern struct {
+	Regexp *regexp.Regexp
+	// Accept checks the found value, if it is not nil.
+	Accept func(value string) bool
+}
+
+var (
+	CardNumberPattern = RedactionPattern{
+		Regexp: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Accept: isCardNumber,
+	}
+	EmailPattern = RedactionPattern{
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	BearerTokenPattern = RedactionPattern{
+		Regexp: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+	}
+)
+
+// DefaultRedactionPatterns are patterns, that are redacted by default.
+var DefaultRedactionPatterns = []RedactionPattern{
+	CardNumberPattern,
+	EmailPattern,
+	BearerTokenPattern,
+}
+
+type RedactionMode int
+
+const (
+	// RedactMask replaces sensitive value with the mask.
+	RedactMask RedactionMode = iota
+	// RedactHash replaces sensitive value with its keyed hash, so equal values stay correlatable.
+	RedactHash
+)
+
+type RedactingOptions struct {
+	// Fields are names of the fields, that are redacted entirely.
+	// Names are compared ignoring case, dashes and underscores. Default is DefaultRedactedFields.
+	Fields []string
+	// Patterns are redacted in values of all fields. Default is DefaultRedactionPatterns.
+	Patterns []RedactionPattern
+	Mode     RedactionMode
+	// Mask replaces the sensitive values in RedactMask mode. Default is "[REDACTED]".
+	Mask string
+	// Salt is the key of the hash in RedactHash mode.
+	Salt string
+}
+
+// RedactingPurifier removes secrets from the logged messages and fields.
+// JSON documents are parsed, so values of the denied keys are redacted at any depth.
+type RedactingPurifier struct {
+	fields   map[string]struct{}
+	patterns []RedactionPattern
+	pairs    *regexp.Regexp
+	mode     RedactionMode
+	mask     string
+	salt     []byte
+	next     Purifier
+}
+
+func NewRedactingPurifier(options RedactingOptions, next Purifier) *RedactingPurifier {
+	if options.Fields == nil {
+		options.Fields = DefaultRedactedFields
+	}
+	if options.Patterns == nil {
+		options.Patterns = DefaultRedactionPatterns
+	}
+	if options.Mask == "" {
+		options.Mask = defaultRedactionMask
+	}
+
+	fields := make(map[string]struct{}, len(options.Fields))
+	names := make([]string, 0, len(options.Fields))
+	for _, field := range options.Fields {
+		fields[normalizeFieldName(field)] = struct{}{}
+		names = append(names, regexp.QuoteMeta(field))
+	}
+
+	var pairs *regexp.Regexp
+	if len(names) != 0 {
+		// key=value and key: value pairs of the plain text
+		pairs = regexp.MustCompile(`(?i)\b(?:` + strings.Join(names, "|") + `)"?\s*[:=]\s*"?([^\s"&,;]+)`)
+	}
+
+	return &RedactingPurifier{
+		fields:   fields,
+		patterns: options.Patterns,
+		pairs:    pairs,
+		mode:     options.Mode,
+		mask:     options.Mask,
+		salt:     []byte(options.Salt),
+		next:     next,
+	}
+}
+
+func (that *RedactingPurifier) Purify(original, derivative string) string {
+	derivative = that.redact(derivative)
+
+	if that.next == nil {
+		return derivative
+	}
+
+	return that.next.Purify(original, derivative)
+}
+
+func (that *RedactingPurifier) PurifyField(key, value string) string {
+	if that.isDenied(key) {
+		value = that.replace(value)
+	} else {
+		value = that.redact(value)
+	}
+
+	return purifyField(that.next, key, value)
+}
+
+func (that *RedactingPurifier) isDenied(key string) bool {
+	_, ok := that.fields[normalizeFieldName(key)]
+	return ok
+}
+
+func (that *RedactingPurifier) redact(s string) string {
+	if canBeJson.MatchString(s) {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(s), &doc); err == nil && isJsonContainer(doc) {
+			// clean documents are returned as is, so they keep their formatting and order of the keys
+			doc, changed := that.redactJson(doc)
+			if !changed {
+				return s
+			}
+			if b, err := json.Marshal(doc); err == nil {
+				return string(b)
+			}
+		}
+	}
+
+	return that.redactText(s)
+}
+
+// redactJson redacts the document in place and reports, whether it is changed.
+func (that *RedactingPurifier) redactJson(doc interface{}) (interface{}, bool) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		changed := false
+		for key, val := range v {
+			if that.isDenied(key) {
+				if s, ok := val.(string); ok {
+					v[key] = that.replace(s)
+				} else {
+					v[key] = that.mask
+				}
+				changed = true
+				continue
+			}
+			var ok bool
+			v[key], ok = that.redactJson(val)
+			changed = changed || ok
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, val := range v {
+			var ok bool
+			v[i], ok = that.redactJson(val)
+			changed = changed || ok
+		}
+		return v, changed
+	case string:
+		s := that.redactText(v)
+		return s, s != v
+	default:
+		return doc, false
+	}
+}
+
+func (that *RedactingPurifier) redactText(s string) string {
+	for _, pattern := range that.patterns {
+		s = that.replaceAll(s, pattern)
+	}
+	if that.pairs != nil {
+		s = that.replaceAll(s, RedactionPattern{Regexp: that.pairs})
+	}
+	return s
+}
+
+func (that *RedactingPurifier) replaceAll(s string, pattern RedactionPattern) string {
+	matches := pattern.Regexp.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		from, to := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			from, to = m[2], m[3]
+		}
+
+		value := s[from:to]
+		if pattern.Accept != nil && !pattern.Accept(value) {
+			continue
+		}
+
+		b.WriteString(s[last:from])
+		b.WriteString(that.replace(value))
+		last = to
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// replace returns replacement of the sensitive value.
+func (that *RedactingPurifier) replace(value string) string {
+	if that.mode != RedactHash {
+		return that.mask
+	}
+
+	h := hmac.New(sha256.New, that.salt)
+	h.Write([]byte(value))
+	return "[HASH:" + hex.EncodeToString(h.Sum(nil)[:8]) + "]"
+}
+
+func isJsonContainer(doc interface{}) bool {
+	switch doc.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func normalizeFieldName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "_", "")
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// isCardNumber validates the number with the Luhn algorithm.
+func isCardNumber(s string) bool {
+	sum := 0
+	digits := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+		double = !double
+	}
+
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
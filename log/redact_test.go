@@ -0,0 +1,102 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/Adverax/core/log/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestRedactingPurifier_Purify(t *testing.T) {
+	p := NewRedactingPurifier(RedactingOptions{}, nil)
+
+	type Test struct {
+		src string
+		dst string
+	}
+
+	tests := map[string]Test{
+		"card number": {
+			src: "paid by 4111 1111 1111 1111 at 12:00",
+			dst: "paid by [REDACTED] at 12:00",
+		},
+		"invalid card number": {
+			src: "order 1234567890123",
+			dst: "order 1234567890123",
+		},
+		"email": {
+			src: "sent to john.doe@example.com",
+			dst: "sent to [REDACTED]",
+		},
+		"bearer token": {
+			src: "header Bearer eyJhbGciOi.eyJzdWIiOi.SflKxw",
+			dst: "header Bearer [REDACTED]",
+		},
+		"query": {
+			src: "GET /login?user=bob&password=qwerty",
+			dst: "GET /login?user=bob&password=[REDACTED]",
+		},
+		"json": {
+			src: `{"user":{"name":"bob","Password":"qwerty","api-key":42},"emails":["bob@example.com"]}`,
+			dst: `{"user":{"name":"bob","Password":"[REDACTED]","api-key":"[REDACTED]"},"emails":["[REDACTED]"]}`,
+		},
+		"clean json": {
+			src: `{"z": 1, "user": {"name": "bob"}, "a": [true, "text"]}`,
+			dst: `{"z": 1, "user": {"name": "bob"}, "a": [true, "text"]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dst := p.Purify(test.src, test.src)
+			if strings.HasPrefix(test.src, "{") && test.src != test.dst {
+				assert.JSONEq(t, test.dst, dst)
+			} else {
+				assert.Equal(t, test.dst, dst)
+			}
+		})
+	}
+}
+
+func TestRedactingPurifier_Hash(t *testing.T) {
+	p := NewRedactingPurifier(RedactingOptions{Mode: RedactHash, Salt: "salt"}, nil)
+
+	a := p.PurifyField("token", "secret-1")
+	b := p.PurifyField("token", "secret-1")
+	c := p.PurifyField("token", "secret-2")
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.True(t, strings.HasPrefix(a, "[HASH:"))
+	assert.NotContains(t, a, "secret")
+
+	other := NewRedactingPurifier(RedactingOptions{Mode: RedactHash, Salt: "pepper"}, nil)
+	assert.NotEqual(t, a, other.PurifyField("token", "secret-1"))
+}
+
+func TestRedactingPurifier_Chain(t *testing.T) {
+	buf := &bytes.Buffer{}
+	purifier := NewMultilinePurifier(NewRedactingPurifier(RedactingOptions{}, nil))
+	l, err := NewLogrusBuilder().
+		Output(buf).
+		Level(InfoLevel).
+		Formatter(&logrus.TemplateFormatter{DisableTimestamp: true, Purifier: purifier}).
+		Build()
+	require.NoError(t, err)
+
+	NewLogger(l, nil).
+		WithFields(context.Background(), Fields{
+			"Authorization": "Basic dXNlcjpwYXNz",
+			"user":          "bob@example.com",
+			FieldKeyData:    "{\n\"password\": \"qwerty\"\n}",
+		}).
+		Info(context.Background(), "login")
+
+	out := buf.String()
+	assert.NotContains(t, out, "dXNlcjpwYXNz")
+	assert.NotContains(t, out, "bob@example.com")
+	assert.NotContains(t, out, "qwerty")
+	assert.Contains(t, out, `{"password":"[REDACTED]"}`)
+}
@@ -0,0 +1,509 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const maxSamplerKeys = 4096
+
+// Sampler decides, whether the entry with the key is written.
+// Key is the message template of the entry, followed by values of the key fields (see SamplingOptions.KeyFields).
+type Sampler interface {
+	Sample(level Level, key string) bool
+}
+
+type samplerKey struct {
+	level Level
+	key   string
+}
+
+type sampleCounter struct {
+	resetAt time.Time
+	count   int
+}
+
+// TemplateSampler writes the first entries of each key per tick
+// and then every M-th of them.
+type TemplateSampler struct {
+	mx         sync.Mutex
+	first      int
+	thereafter int
+	tick       time.Duration
+	counters   map[samplerKey]*sampleCounter
+	now        func() time.Time
+}
+
+// NewTemplateSampler makes sampler, that writes the first entries of each key per tick
+// and then every thereafter-th of them. Zero thereafter drops the rest of the entries.
+func NewTemplateSampler(first, thereafter int, tick time.Duration) *TemplateSampler {
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	return &TemplateSampler{
+		first:      first,
+		thereafter: thereafter,
+		tick:       tick,
+		counters:   make(map[samplerKey]*sampleCounter),
+		now:        time.Now,
+	}
+}
+
+func (that *TemplateSampler) Sample(level Level, key string) bool {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	now := that.now()
+	k := samplerKey{level: level, key: key}
+	counter, ok := that.counters[k]
+	if !ok {
+		if len(that.counters) >= maxSamplerKeys {
+			that.purge(now)
+		}
+		counter = &sampleCounter{}
+		that.counters[k] = counter
+	}
+
+	if !now.Before(counter.resetAt) {
+		counter.count = 0
+		counter.resetAt = now.Add(that.tick)
+	}
+
+	counter.count++
+	if counter.count <= that.first {
+		return true
+	}
+	if that.thereafter <= 0 {
+		return false
+	}
+	return (counter.count-that.first)%that.thereafter == 0
+}
+
+// purge removes the counters of the passed ticks.
+func (that *TemplateSampler) purge(now time.Time) {
+	for key, counter := range that.counters {
+		if !now.Before(counter.resetAt) {
+			delete(that.counters, key)
+		}
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter limits rate of the entries of each key with the token bucket.
+type RateLimiter struct {
+	mx      sync.Mutex
+	rate    float64
+	burst   int
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// NewRateLimiter makes limiter, that writes rate entries per second of each key
+// with bursts up to burst entries.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (that *RateLimiter) Sample(level Level, key string) bool {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	now := that.now()
+	bucket, ok := that.buckets[key]
+	if !ok {
+		if len(that.buckets) >= maxSamplerKeys {
+			that.purge(now)
+		}
+		bucket = &tokenBucket{tokens: float64(that.burst), last: now}
+		that.buckets[key] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * that.rate
+	if bucket.tokens > float64(that.burst) {
+		bucket.tokens = float64(that.burst)
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// purge removes the full buckets, because they are equal to the new ones.
+func (that *RateLimiter) purge(now time.Time) {
+	for key, bucket := range that.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*that.rate >= float64(that.burst) {
+			delete(that.buckets, key)
+		}
+	}
+}
+
+type SamplingOptions struct {
+	// Level is the most severe level, that is sampled. Entries of the more severe levels are always written.
+	// Default is InfoLevel. Fatal and panic entries are never sampled.
+	Level Level
+	// Interval is period of the summary entry about the dropped entries. Zero disables the summary.
+	Interval time.Duration
+	// KeyFields are names of the fields, whose values are added to the key of the entry,
+	// so the entries are sampled separately, e.g. per user or tenant.
+	// Values are taken from WithField, WithFields and the typed fields of the entry.
+	KeyFields []string
+}
+
+type sampling struct {
+	sampler  Sampler
+	level    Level
+	interval time.Duration
+	keys     []string
+	dropped  uint64
+	once     sync.Once
+}
+
+// SampledLogger is Logger, that drops the entries rejected by the sampler.
+// Message template of the entry is its format string, message or the first string argument.
+type SampledLogger struct {
+	logger Logger
+	values []string
+	*sampling
+}
+
+func NewSampledLogger(logger Logger, sampler Sampler, options SamplingOptions) *SampledLogger {
+	if options.Level == PanicLevel {
+		options.Level = InfoLevel
+	}
+
+	return &SampledLogger{
+		logger: logger,
+		values: make([]string, len(options.KeyFields)),
+		sampling: &sampling{
+			sampler:  sampler,
+			level:    options.Level,
+			interval: options.Interval,
+			keys:     options.KeyFields,
+		},
+	}
+}
+
+// Start writes the summary entries periodically until the context is done.
+func (that *SampledLogger) Start(ctx context.Context) {
+	if that.interval <= 0 {
+		return
+	}
+
+	that.once.Do(func() {
+		go func() {
+			ticker := time.NewTicker(that.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					that.Report(context.Background())
+					return
+				case <-ticker.C:
+					that.Report(ctx)
+				}
+			}
+		}()
+	})
+}
+
+// Dropped returns number of the entries dropped since the last summary.
+func (that *SampledLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&that.dropped)
+}
+
+// Report writes the summary entry about the entries dropped since the last summary.
+func (that *SampledLogger) Report(ctx context.Context) {
+	dropped := atomic.SwapUint64(&that.dropped, 0)
+	if dropped == 0 {
+		return
+	}
+
+	Logw(ctx, that.logger, WarnLevel, "log entries are dropped by sampling", Int64("dropped", int64(dropped)))
+}
+
+func (that *SampledLogger) allow(ctx context.Context, level Level, template string, fields ...Field) bool {
+	if level <= FatalLevel || level < that.level {
+		return true
+	}
+	if !IsEnabled(ctx, that.logger, level) {
+		return false
+	}
+	if that.sampler.Sample(level, that.key(template, fields)) {
+		return true
+	}
+
+	atomic.AddUint64(&that.dropped, 1)
+	return false
+}
+
+// key returns key of the entry made from the template and values of the key fields.
+func (that *SampledLogger) key(template string, fields []Field) string {
+	if len(that.keys) == 0 {
+		return template
+	}
+
+	values := that.values
+	for _, f := range fields {
+		values = that.setKey(values, f.Key, f.Value())
+	}
+
+	var b strings.Builder
+	b.WriteString(template)
+	for _, value := range values {
+		b.WriteByte(0)
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+func (that *SampledLogger) keyIndex(key string) int {
+	for i, k := range that.keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// setKey returns values of the key fields with the value of the field.
+// Values of the logger are copied before the change, because they are shared with its entries.
+func (that *SampledLogger) setKey(values []string, key string, value interface{}) []string {
+	i := that.keyIndex(key)
+	if i < 0 {
+		return values
+	}
+
+	if len(values) != 0 && &values[0] == &that.values[0] {
+		values = append([]string(nil), values...)
+	}
+	values[i] = fmt.Sprint(value)
+	return values
+}
+
+func (that *SampledLogger) with(logger Logger, values []string) Logger {
+	return &SampledLogger{logger: logger, values: values, sampling: that.sampling}
+}
+
+func (that *SampledLogger) NewContext(ctx context.Context) context.Context {
+	return that.logger.NewContext(ctx)
+}
+
+func (that *SampledLogger) WithField(ctx context.Context, key string, value interface{}) Logger {
+	return that.with(that.logger.WithField(ctx, key, value), that.setKey(that.values, key, value))
+}
+
+func (that *SampledLogger) WithFields(ctx context.Context, fields Fields) Logger {
+	values := that.values
+	for key, value := range fields {
+		values = that.setKey(values, key, value)
+	}
+	return that.with(that.logger.WithFields(ctx, fields), values)
+}
+
+func (that *SampledLogger) WithError(ctx context.Context, err error) Logger {
+	return that.with(that.logger.WithError(ctx, err), that.values)
+}
+
+func (that *SampledLogger) Enabled(ctx context.Context, level Level) bool {
+	return IsEnabled(ctx, that.logger, level)
+}
+
+func (that *SampledLogger) Tracef(ctx context.Context, format string, args ...interface{}) {
+	if that.allow(ctx, TraceLevel, format) {
+		that.logger.Tracef(ctx, format, args...)
+	}
+}
+
+func (that *SampledLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	if that.allow(ctx, DebugLevel, format) {
+		that.logger.Debugf(ctx, format, args...)
+	}
+}
+
+func (that *SampledLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	if that.allow(ctx, InfoLevel, format) {
+		that.logger.Infof(ctx, format, args...)
+	}
+}
+
+func (that *SampledLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	if that.allow(ctx, WarnLevel, format) {
+		that.logger.Warningf(ctx, format, args...)
+	}
+}
+
+func (that *SampledLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	if that.allow(ctx, ErrorLevel, format) {
+		that.logger.Errorf(ctx, format, args...)
+	}
+}
+
+func (that *SampledLogger) Fatalf(ctx context.Context, format string, args ...interface{}) {
+	if that.allow(ctx, FatalLevel, format) {
+		that.logger.Fatalf(ctx, format, args...)
+	}
+}
+
+func (that *SampledLogger) Panicf(ctx context.Context, format string, args ...interface{}) {
+	if that.allow(ctx, PanicLevel, format) {
+		that.logger.Panicf(ctx, format, args...)
+	}
+}
+
+func (that *SampledLogger) Trace(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, TraceLevel, templateOf(args)) {
+		that.logger.Trace(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Debug(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, DebugLevel, templateOf(args)) {
+		that.logger.Debug(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Info(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, InfoLevel, templateOf(args)) {
+		that.logger.Info(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Warning(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, WarnLevel, templateOf(args)) {
+		that.logger.Warning(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Error(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, ErrorLevel, templateOf(args)) {
+		that.logger.Error(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Fatal(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, FatalLevel, templateOf(args)) {
+		that.logger.Fatal(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Panic(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, PanicLevel, templateOf(args)) {
+		that.logger.Panic(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Traceln(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, TraceLevel, templateOf(args)) {
+		that.logger.Traceln(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Debugln(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, DebugLevel, templateOf(args)) {
+		that.logger.Debugln(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Infoln(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, InfoLevel, templateOf(args)) {
+		that.logger.Infoln(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Warningln(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, WarnLevel, templateOf(args)) {
+		that.logger.Warningln(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Errorln(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, ErrorLevel, templateOf(args)) {
+		that.logger.Errorln(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Fatalln(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, FatalLevel, templateOf(args)) {
+		that.logger.Fatalln(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Panicln(ctx context.Context, args ...interface{}) {
+	if that.allow(ctx, PanicLevel, templateOf(args)) {
+		that.logger.Panicln(ctx, args...)
+	}
+}
+
+func (that *SampledLogger) Tracew(ctx context.Context, msg string, fields ...Field) {
+	if that.allow(ctx, TraceLevel, msg, fields...) {
+		Logw(ctx, that.logger, TraceLevel, msg, fields...)
+	}
+}
+
+func (that *SampledLogger) Debugw(ctx context.Context, msg string, fields ...Field) {
+	if that.allow(ctx, DebugLevel, msg, fields...) {
+		Logw(ctx, that.logger, DebugLevel, msg, fields...)
+	}
+}
+
+func (that *SampledLogger) Infow(ctx context.Context, msg string, fields ...Field) {
+	if that.allow(ctx, InfoLevel, msg, fields...) {
+		Logw(ctx, that.logger, InfoLevel, msg, fields...)
+	}
+}
+
+func (that *SampledLogger) Warningw(ctx context.Context, msg string, fields ...Field) {
+	if that.allow(ctx, WarnLevel, msg, fields...) {
+		Logw(ctx, that.logger, WarnLevel, msg, fields...)
+	}
+}
+
+func (that *SampledLogger) Errorw(ctx context.Context, msg string, fields ...Field) {
+	if that.allow(ctx, ErrorLevel, msg, fields...) {
+		Logw(ctx, that.logger, ErrorLevel, msg, fields...)
+	}
+}
+
+func (that *SampledLogger) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	if that.allow(ctx, FatalLevel, msg, fields...) {
+		Logw(ctx, that.logger, FatalLevel, msg, fields...)
+	}
+}
+
+func (that *SampledLogger) Panicw(ctx context.Context, msg string, fields ...Field) {
+	if that.allow(ctx, PanicLevel, msg, fields...) {
+		Logw(ctx, that.logger, PanicLevel, msg, fields...)
+	}
+}
+
+// templateOf returns template of the message made from the arguments.
+func templateOf(args []interface{}) string {
+	if len(args) != 0 {
+		if s, ok := args[0].(string); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(args...)
+}
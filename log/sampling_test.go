@@ -0,0 +1,130 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type manualTime struct {
+	now time.Time
+}
+
+func (that *manualTime) Now() time.Time {
+	return that.now
+}
+
+func TestTemplateSampler(t *testing.T) {
+	clock := &manualTime{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewTemplateSampler(2, 3, time.Second)
+	s.now = clock.Now
+
+	var sampled []bool
+	for i := 0; i < 8; i++ {
+		sampled = append(sampled, s.Sample(InfoLevel, "message %d"))
+	}
+	assert.Equal(t, []bool{true, true, false, false, true, false, false, true}, sampled)
+	assert.True(t, s.Sample(InfoLevel, "other"))
+	assert.True(t, s.Sample(DebugLevel, "message %d"))
+
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, s.Sample(InfoLevel, "message %d"))
+}
+
+func TestRateLimiter(t *testing.T) {
+	clock := &manualTime{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewRateLimiter(2, 2)
+	l.now = clock.Now
+
+	assert.True(t, l.Sample(InfoLevel, "a"))
+	assert.True(t, l.Sample(InfoLevel, "a"))
+	assert.False(t, l.Sample(InfoLevel, "a"))
+	assert.True(t, l.Sample(InfoLevel, "b"))
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	assert.True(t, l.Sample(InfoLevel, "a"))
+	assert.False(t, l.Sample(InfoLevel, "a"))
+}
+
+func TestSampledLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sampler := NewTemplateSampler(1, 0, time.Hour)
+	l := NewSampledLogger(newFieldsLogger(t, buf, DebugLevel), sampler, SamplingOptions{})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		l.Infof(ctx, "request %d", i)
+		Logw(ctx, l.WithField(ctx, "id", i), DebugLevel, "debug")
+		l.Warningf(ctx, "warning %d", i)
+	}
+	l.Trace(ctx, "disabled")
+	assert.Equal(t, uint64(4), l.Dropped())
+
+	l.Report(ctx)
+	assert.Equal(t, uint64(0), l.Dropped())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 6)
+	assert.JSONEq(t, `{"level": "info", "msg": "request 0"}`, lines[0])
+	assert.JSONEq(t, `{"level": "debug", "msg": "debug", "id": 0}`, lines[1])
+	assert.JSONEq(t, `{"level": "warning", "msg": "log entries are dropped by sampling", "dropped": 4}`, lines[5])
+}
+
+func TestSampledLogger_KeyFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sampler := NewRateLimiter(0, 1)
+	l := NewSampledLogger(newFieldsLogger(t, buf, InfoLevel), sampler, SamplingOptions{KeyFields: []string{"user"}})
+	ctx := context.Background()
+
+	alice := l.WithField(ctx, "user", "alice")
+	for i := 0; i < 2; i++ {
+		alice.Infof(ctx, "request %d", i)
+		l.WithFields(ctx, Fields{"user": "bob", "id": i}).Infof(ctx, "request %d", i)
+		Logw(ctx, alice, InfoLevel, "login", Str("user", "carol"))
+		Logw(ctx, alice.WithField(ctx, "id", i), InfoLevel, "login")
+	}
+	assert.Equal(t, uint64(4), l.Dropped())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.JSONEq(t, `{"level": "info", "msg": "request 0", "user": "alice"}`, lines[0])
+	assert.JSONEq(t, `{"level": "info", "msg": "request 0", "user": "bob", "id": 0}`, lines[1])
+	assert.JSONEq(t, `{"level": "info", "msg": "login", "user": "carol"}`, lines[2])
+	assert.JSONEq(t, `{"level": "info", "msg": "login", "user": "alice", "id": 0}`, lines[3])
+}
+
+func TestSampledLogger_Start(t *testing.T) {
+	buf := &lockedBuffer{}
+	sampler := NewTemplateSampler(0, 0, time.Hour)
+	l := NewSampledLogger(newFieldsLogger(t, buf, InfoLevel), sampler, SamplingOptions{Interval: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l.Start(ctx)
+
+	l.Info(ctx, "dropped")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), `"dropped":1`)
+	}, time.Second, time.Millisecond)
+}
+
+type lockedBuffer struct {
+	mx  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (that *lockedBuffer) Write(p []byte) (int, error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+	return that.buf.Write(p)
+}
+
+func (that *lockedBuffer) String() string {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+	return that.buf.String()
+}
@@ -0,0 +1,389 @@
+//go:build go1.21
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"github.com/Adverax/core/log/logrus"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Extra levels of the slog, that have no direct equivalents.
+const (
+	SlogLevelTrace = slog.LevelDebug - 4
+	SlogLevelFatal = slog.LevelError + 4
+	SlogLevelPanic = slog.LevelError + 8
+)
+
+// SlogHandler is slog.Handler, that writes records through the Logger.
+// Groups are flattened into the dotted keys of the fields.
+type SlogHandler struct {
+	logger Logger
+	prefix string
+	fields []Field
+}
+
+// NewSlogHandler makes handler, that writes records through the logger.
+// If logger is nil, the logger is resolved from the context of each record
+// (see NewContext) and falls back to the SysLog.
+func NewSlogHandler(logger Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+func (that *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return IsEnabled(ctx, that.resolve(ctx), levelFromSlog(level))
+}
+
+func (that *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make([]Field, len(that.fields), len(that.fields)+record.NumAttrs())
+	copy(fields, that.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendSlogAttr(fields, that.prefix, attr)
+		return true
+	})
+
+	Logw(ctx, that.resolve(ctx), levelFromSlog(record.Level), record.Message, fields...)
+
+	return nil
+}
+
+func (that *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return that
+	}
+
+	h := *that
+	h.fields = make([]Field, len(that.fields), len(that.fields)+len(attrs))
+	copy(h.fields, that.fields)
+	for _, attr := range attrs {
+		h.fields = appendSlogAttr(h.fields, that.prefix, attr)
+	}
+	return &h
+}
+
+func (that *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return that
+	}
+
+	h := *that
+	h.prefix = that.prefix + name + "."
+	return &h
+}
+
+func (that *SlogHandler) resolve(ctx context.Context) Logger {
+	if that.logger != nil {
+		return that.logger
+	}
+
+	return getLogger(ctx, SysLog)
+}
+
+// levelFromSlog maps level of the slog to the nearest level of the Logger.
+// Records above the error level are written as errors, because handler must not stop the application.
+func levelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+func levelToSlog(level Level) slog.Level {
+	switch level {
+	case TraceLevel:
+		return SlogLevelTrace
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel:
+		return SlogLevelFatal
+	default:
+		return SlogLevelPanic
+	}
+}
+
+func appendSlogAttr(fields []Field, prefix string, attr slog.Attr) []Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			fields = appendSlogAttr(fields, prefix, a)
+		}
+		return fields
+	}
+
+	key := prefix + attr.Key
+	val := attr.Value
+	switch val.Kind() {
+	case slog.KindString:
+		return append(fields, Str(key, val.String()))
+	case slog.KindInt64:
+		return append(fields, Int64(key, val.Int64()))
+	case slog.KindFloat64:
+		return append(fields, Float64(key, val.Float64()))
+	case slog.KindBool:
+		return append(fields, Bool(key, val.Bool()))
+	case slog.KindDuration:
+		return append(fields, Duration(key, val.Duration()))
+	}
+
+	if err, ok := val.Any().(error); ok {
+		return append(fields, Field{Key: key, Type: ErrorType, Interface: err})
+	}
+
+	return append(fields, Any(key, val.Any()))
+}
+
+// slogLogger is Logger, that writes entries to the slog.Handler.
+type slogLogger struct {
+	handler slog.Handler
+	errors  ErrorTuner
+}
+
+// NewSlogLogger makes Logger backed by the handler.
+// Context of each call is passed to the handler, so handler can extract values of the context.
+func NewSlogLogger(handler slog.Handler, errors ErrorTuner) Logger {
+	if errors == nil {
+		errors = ErrorTunerFunc(func(err error) error {
+			return err
+		})
+	}
+
+	return &slogLogger{handler: handler, errors: errors}
+}
+
+func (that *slogLogger) NewContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (that *slogLogger) WithField(ctx context.Context, key string, value interface{}) Logger {
+	return that.with(slog.Any(key, value))
+}
+
+func (that *slogLogger) WithFields(ctx context.Context, fields Fields) Logger {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for k, v := range fields {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	return that.with(attrs...)
+}
+
+func (that *slogLogger) WithError(ctx context.Context, err error) Logger {
+	return that.with(slog.Any(logrus.ErrorKey, that.errors.TuneError(err)))
+}
+
+func (that *slogLogger) with(attrs ...slog.Attr) Logger {
+	return &slogLogger{handler: that.handler.WithAttrs(attrs), errors: that.errors}
+}
+
+func (that *slogLogger) Enabled(ctx context.Context, level Level) bool {
+	return that.handler.Enabled(ctx, levelToSlog(level))
+}
+
+func (that *slogLogger) Tracef(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ctx, TraceLevel, format, args)
+}
+
+func (that *slogLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ctx, DebugLevel, format, args)
+}
+
+func (that *slogLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ctx, InfoLevel, format, args)
+}
+
+func (that *slogLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ctx, WarnLevel, format, args)
+}
+
+func (that *slogLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ctx, ErrorLevel, format, args)
+}
+
+func (that *slogLogger) Fatalf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ctx, FatalLevel, format, args)
+}
+
+func (that *slogLogger) Panicf(ctx context.Context, format string, args ...interface{}) {
+	that.logf(ctx, PanicLevel, format, args)
+}
+
+func (that *slogLogger) Trace(ctx context.Context, args ...interface{}) {
+	that.log(ctx, TraceLevel, args)
+}
+
+func (that *slogLogger) Debug(ctx context.Context, args ...interface{}) {
+	that.log(ctx, DebugLevel, args)
+}
+
+func (that *slogLogger) Info(ctx context.Context, args ...interface{}) {
+	that.log(ctx, InfoLevel, args)
+}
+
+func (that *slogLogger) Warning(ctx context.Context, args ...interface{}) {
+	that.log(ctx, WarnLevel, args)
+}
+
+func (that *slogLogger) Error(ctx context.Context, args ...interface{}) {
+	that.log(ctx, ErrorLevel, args)
+}
+
+func (that *slogLogger) Fatal(ctx context.Context, args ...interface{}) {
+	that.log(ctx, FatalLevel, args)
+}
+
+func (that *slogLogger) Panic(ctx context.Context, args ...interface{}) {
+	that.log(ctx, PanicLevel, args)
+}
+
+func (that *slogLogger) Traceln(ctx context.Context, args ...interface{}) {
+	that.logln(ctx, TraceLevel, args)
+}
+
+func (that *slogLogger) Debugln(ctx context.Context, args ...interface{}) {
+	that.logln(ctx, DebugLevel, args)
+}
+
+func (that *slogLogger) Infoln(ctx context.Context, args ...interface{}) {
+	that.logln(ctx, InfoLevel, args)
+}
+
+func (that *slogLogger) Warningln(ctx context.Context, args ...interface{}) {
+	that.logln(ctx, WarnLevel, args)
+}
+
+func (that *slogLogger) Errorln(ctx context.Context, args ...interface{}) {
+	that.logln(ctx, ErrorLevel, args)
+}
+
+func (that *slogLogger) Fatalln(ctx context.Context, args ...interface{}) {
+	that.logln(ctx, FatalLevel, args)
+}
+
+func (that *slogLogger) Panicln(ctx context.Context, args ...interface{}) {
+	that.logln(ctx, PanicLevel, args)
+}
+
+func (that *slogLogger) Tracew(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ctx, TraceLevel, msg, fields)
+}
+
+func (that *slogLogger) Debugw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ctx, DebugLevel, msg, fields)
+}
+
+func (that *slogLogger) Infow(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ctx, InfoLevel, msg, fields)
+}
+
+func (that *slogLogger) Warningw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ctx, WarnLevel, msg, fields)
+}
+
+func (that *slogLogger) Errorw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ctx, ErrorLevel, msg, fields)
+}
+
+func (that *slogLogger) Fatalw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ctx, FatalLevel, msg, fields)
+}
+
+func (that *slogLogger) Panicw(ctx context.Context, msg string, fields ...Field) {
+	that.logw(ctx, PanicLevel, msg, fields)
+}
+
+func (that *slogLogger) logf(ctx context.Context, level Level, format string, args []interface{}) {
+	if !that.Enabled(ctx, level) {
+		that.skip(level)
+		return
+	}
+
+	that.write(ctx, level, fmt.Sprintf(format, args...), nil)
+}
+
+func (that *slogLogger) log(ctx context.Context, level Level, args []interface{}) {
+	if !that.Enabled(ctx, level) {
+		that.skip(level)
+		return
+	}
+
+	that.write(ctx, level, fmt.Sprint(args...), nil)
+}
+
+func (that *slogLogger) logln(ctx context.Context, level Level, args []interface{}) {
+	if !that.Enabled(ctx, level) {
+		that.skip(level)
+		return
+	}
+
+	msg := fmt.Sprintln(args...)
+	that.write(ctx, level, strings.TrimSuffix(msg, "\n"), nil)
+}
+
+func (that *slogLogger) logw(ctx context.Context, level Level, msg string, fields []Field) {
+	if !that.Enabled(ctx, level) {
+		that.skip(level)
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		if f.Type != ErrorType {
+			attrs = append(attrs, slog.Any(f.Key, f.Value()))
+			continue
+		}
+
+		if err, ok := f.Interface.(error); ok && err != nil {
+			attrs = append(attrs, slog.Any(f.Key, that.errors.TuneError(err)))
+		}
+	}
+
+	that.write(ctx, level, msg, attrs)
+}
+
+// write passes the record to the handler.
+// Fatal entries exit and panic entries panic after the record is written, as the logrus does.
+func (that *slogLogger) write(ctx context.Context, level Level, msg string, attrs []slog.Attr) {
+	record := slog.NewRecord(time.Now(), levelToSlog(level), msg, 0)
+	record.AddAttrs(attrs...)
+	if err := that.handler.Handle(ctx, record); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+
+	switch level {
+	case FatalLevel:
+		os.Exit(1)
+	case PanicLevel:
+		panic(msg)
+	}
+}
+
+// skip exits on the disabled fatal entry, as the logrus does.
+func (that *slogLogger) skip(level Level) {
+	if level == FatalLevel {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,120 @@
+//go:build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSlogHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := slog.New(NewSlogHandler(newFieldsLogger(t, buf, DebugLevel)))
+
+	l.With("service", "users").
+		WithGroup("request").
+		Warn(
+			"message",
+			"id", 5,
+			slog.Group("user", slog.String("name", "bob")),
+			slog.Bool("ok", false),
+			slog.Any("err", errors.New("failure")),
+		)
+	l.Log(context.Background(), SlogLevelTrace, "hidden")
+
+	assert.JSONEq(t, `{
+		"level": "warning",
+		"msg": "message",
+		"service": "users",
+		"request.id": 5,
+		"request.user.name": "bob",
+		"request.ok": false,
+		"request.err": "failure"
+	}`, buf.String())
+}
+
+func TestSlogHandler_ResolvesLoggerFromContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := NewContext(context.Background(), newFieldsLogger(t, buf, InfoLevel))
+	l := slog.New(NewSlogHandler(nil))
+
+	l.InfoContext(ctx, "message", "id", 5)
+	l.DebugContext(ctx, "hidden")
+
+	assert.JSONEq(t, `{"level": "info", "msg": "message", "id": 5}`, buf.String())
+}
+
+func TestSlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	l := NewSlogLogger(handler, nil)
+	ctx := context.Background()
+
+	assert.True(t, IsEnabled(ctx, l, DebugLevel))
+	assert.False(t, IsEnabled(ctx, l, TraceLevel))
+
+	entry := l.WithField(ctx, "entity", "USER").WithError(ctx, errors.New("failure"))
+	Logw(ctx, entry, WarnLevel, "message", Int("id", 5), Duration("duration", time.Second))
+	l.Trace(ctx, "hidden")
+
+	assert.JSONEq(t, `{
+		"level": "WARN",
+		"msg": "message",
+		"entity": "USER",
+		"error": "failure",
+		"id": 5,
+		"duration": 1000000000
+	}`, buf.String())
+}
+
+func TestSlogLogger_PassesContext(t *testing.T) {
+	handler := &contextHandler{}
+	l := NewContextLogger(NewSlogLogger(handler, nil), ContextModeTransparent)
+	ctx := context.WithValue(context.Background(), contextHandlerKey{}, "value")
+	ctx = l.NewContext(ctx)
+
+	Resolve(ctx).Infof(ctx, "message %d", 1)
+
+	require.Len(t, handler.records, 1)
+	assert.Equal(t, "message 1", handler.records[0])
+	assert.Equal(t, "value", handler.values[0])
+}
+
+type contextHandlerKey struct{}
+
+type contextHandler struct {
+	records []string
+	values  []interface{}
+}
+
+func (that *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+func (that *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	that.records = append(that.records, record.Message)
+	that.values = append(that.values, ctx.Value(contextHandlerKey{}))
+	return nil
+}
+
+func (that *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return that
+}
+
+func (that *contextHandler) WithGroup(name string) slog.Handler {
+	return that
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"github.com/Adverax/core/log/logrus"
+)
+
+type Frame = logrus.Frame
+
+type Stack = logrus.Stack
+
+type ErrorInfo = logrus.ErrorInfo
+
+type ErrorChain = logrus.ErrorChain
+
+// WithStack annotates the error with the stack of its creation.
+// The formatters with ErrorChain option render the stack with the chain of the logged error.
+func WithStack(err error) error {
+	return logrus.WithStack(err)
+}
+
+// UnwrapError returns the chain of the wrapped and joined errors, including core.Errors.
+func UnwrapError(err error) ErrorChain {
+	return logrus.UnwrapError(err)
+}
+
+// StackTuner annotates the logged errors with the stack of the log site,
+// unless they already have the stack of their creation.
+type StackTuner struct {
+	next ErrorTuner
+}
+
+func NewStackTuner(next ErrorTuner) *StackTuner {
+	return &StackTuner{next: next}
+}
+
+func (that *StackTuner) TuneError(err error) error {
+	if that.next != nil {
+		err = that.next.TuneError(err)
+	}
+
+	return logrus.WithStack(err)
+}
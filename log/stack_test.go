@@ -0,0 +1,58 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Adverax/core"
+	"github.com/Adverax/core/log/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUnwrapError_CoreErrors(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	chain := UnwrapError(core.NewErrors(errFirst, WithStack(errSecond)))
+	require.Len(t, chain, 3)
+	assert.Equal(t, "*core.Errors", chain[0].Type)
+	assert.Equal(t, ErrorInfo{Message: "first", Type: "*errors.errorString", Depth: 1}, chain[1])
+	assert.Equal(t, "second", chain[2].Message)
+	assert.NotEmpty(t, chain[2].Stack)
+}
+
+func TestStackTuner(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l, err := NewLogrusBuilder().
+		Output(buf).
+		Formatter(&logrus.JSONFormatter{DisableTimestamp: true, ErrorChain: true}).
+		Build()
+	require.NoError(t, err)
+
+	errFailed := errors.New("failed")
+	logger := NewLogger(l, NewStackTuner(ErrorTunerFunc(func(err error) error {
+		return err
+	})))
+	ctx := context.Background()
+
+	logger.WithError(ctx, errFailed).Error(ctx, "request is failed")
+	Logw(ctx, logger, ErrorLevel, "request is failed", Err(WithStack(errFailed)))
+
+	decoder := json.NewDecoder(buf)
+	for i := 0; i < 2; i++ {
+		var doc struct {
+			Error  string     `json:"error"`
+			Errors ErrorChain `json:"errors"`
+		}
+		require.NoError(t, decoder.Decode(&doc))
+		assert.Equal(t, "failed", doc.Error)
+		require.Len(t, doc.Errors, 1)
+		require.NotEmpty(t, doc.Errors[0].Stack)
+		for _, frame := range doc.Errors[0].Stack {
+			assert.NotContains(t, frame.Function, "log.(*StackTuner)")
+		}
+	}
+}
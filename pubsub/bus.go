@@ -0,0 +1,184 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/Adverax/core/bus"
+	"sync"
+)
+
+type contextMakerType int
+
+const contextMakerKey contextMakerType = 0
+
+// ChannelPin exposes bus.Channel as Pin, so it can be exported through the Gateway.
+type ChannelPin[T any] struct {
+	mx           sync.Mutex
+	channel      bus.Channel[T]
+	exporters    *Exporters[T]
+	subscription bus.Subscription
+}
+
+func NewChannelPin[T any](channel bus.Channel[T]) *ChannelPin[T] {
+	return &ChannelPin[T]{
+		channel:   channel,
+		exporters: NewExporters[T](),
+	}
+}
+
+func (that *ChannelPin[T]) Subject() string {
+	return that.channel.Subject()
+}
+
+func (that *ChannelPin[T]) Attach(exporter Exporter) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if that.subscription == nil {
+		that.subscription = that.channel.Subscribe(context.Background(), that.export)
+	}
+
+	that.exporters.Attach(exporter)
+}
+
+func (that *ChannelPin[T]) Import(
+	ctx context.Context,
+	maker string,
+	entity []byte,
+) error {
+	e, err := parse[T](entity)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, contextMakerKey, maker)
+	return that.channel.Publish(ctx, e)
+}
+
+// Close detaches pin from the channel.
+func (that *ChannelPin[T]) Close() {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if that.subscription != nil {
+		that.subscription.Unsubscribe()
+		that.subscription = nil
+	}
+}
+
+func (that *ChannelPin[T]) export(ctx context.Context, notification T) error {
+	maker, _ := ctx.Value(contextMakerKey).(string)
+	that.exporters.Export(
+		ctx,
+		&Event[T]{
+			ctx:      ctx,
+			observer: dummyObserver,
+			subject:  that.channel.Subject(),
+			maker:    maker,
+			entity:   notification,
+		},
+	)
+	return nil
+}
+
+// BusChannel adapts PubSub to the bus.Channel interface.
+// The PubSub is owned by the caller, so Close removes only the subscriptions made through the channel.
+type BusChannel[T any] struct {
+	mx     sync.Mutex
+	pubsub *PubSub[T]
+	logger bus.Logger
+	subs   map[string]struct{}
+	closed bool
+}
+
+func NewBusChannel[T any](pubsub *PubSub[T], logger bus.Logger) *BusChannel[T] {
+	return &BusChannel[T]{
+		pubsub: pubsub,
+		logger: logger,
+		subs:   make(map[string]struct{}),
+	}
+}
+
+func (that *BusChannel[T]) Subject() string {
+	return that.pubsub.Subject()
+}
+
+func (that *BusChannel[T]) Notify(ctx context.Context, notification T) {
+	if that.isClosed() {
+		return
+	}
+
+	that.pubsub.Publish(ctx, notification)
+}
+
+// Publish posts notification and waits until it is handled by all subscribers.
+func (that *BusChannel[T]) Publish(ctx context.Context, notification T) error {
+	if that.isClosed() {
+		return bus.ErrChannelClosed
+	}
+
+	return that.pubsub.Publish(ctx, notification).Wait()
+}
+
+func (that *BusChannel[T]) Subscribe(ctx context.Context, subscriber bus.Subscriber[T]) bus.Subscription {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if that.closed {
+		return &busSubscription[T]{channel: that}
+	}
+
+	sub := that.pubsub.SubscribeHandlerFunc(
+		ctx,
+		func(ctx context.Context, event *Event[T]) {
+			err := subscriber(ctx, event.Entity())
+			if err != nil && that.logger != nil {
+				that.logger.Error(ctx, err.Error())
+			}
+		},
+	)
+	that.subs[sub.ID()] = struct{}{}
+
+	return &busSubscription[T]{channel: that, id: sub.ID()}
+}
+
+// Close removes the subscriptions made through the channel. The PubSub is left open.
+func (that *BusChannel[T]) Close() {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if that.closed {
+		return
+	}
+
+	that.closed = true
+	for id := range that.subs {
+		that.pubsub.Unsubscribe(context.Background(), id)
+	}
+	that.subs = nil
+}
+
+func (that *BusChannel[T]) isClosed() bool {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	return that.closed
+}
+
+func (that *BusChannel[T]) unsubscribe(id string) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if _, ok := that.subs[id]; ok {
+		delete(that.subs, id)
+		that.pubsub.Unsubscribe(context.Background(), id)
+	}
+}
+
+type busSubscription[T any] struct {
+	channel *BusChannel[T]
+	id      string
+}
+
+func (that *busSubscription[T]) Unsubscribe() {
+	that.channel.unsubscribe(that.id)
+}
@@ -0,0 +1,117 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/Adverax/core/bus"
+	"github.com/Adverax/core/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+type publisherMock struct {
+	mx       sync.Mutex
+	wg       sync.WaitGroup
+	messages map[string]string
+}
+
+func (that *publisherMock) Publish(ctx context.Context, subject string, entity json.RawMessage) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.messages[subject] = string(entity)
+	that.wg.Done()
+}
+
+type filterAll struct{}
+
+func (that *filterAll) IsMatch(text string) bool {
+	return true
+}
+
+type ChannelBus struct {
+	OnCreated *ChannelPin[*Receipt]
+}
+
+func TestChannelPin(t *testing.T) {
+	ctx := context.Background()
+	channel := bus.NewChannel[*Receipt]("receipt.created", nil)
+	b := &ChannelBus{OnCreated: NewChannelPin[*Receipt](channel)}
+
+	pub := &publisherMock{messages: make(map[string]string)}
+	gateway := NewGateway(b)
+	gateway.pub = pub
+	gateway.filter = &filterAll{}
+
+	var imported []string
+	channel.Subscribe(ctx, func(ctx context.Context, receipt *Receipt) error {
+		imported = append(imported, receipt.Id)
+		return nil
+	})
+
+	pub.wg.Add(1)
+	require.NoError(t, channel.Publish(ctx, &Receipt{Id: "1"}))
+	pub.wg.Wait()
+	assert.Equal(t, map[string]string{"receipt.created": `{"id":"1"}`}, pub.messages)
+
+	err := gateway.Import(ctx, "receipt.created", json.RawMessage(`{"id":"2"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, imported)
+	assert.Len(t, pub.messages, 1)
+}
+
+func TestBusChannel(t *testing.T) {
+	ctx := context.Background()
+	ps, err := newTestPubSub[int]()
+	require.NoError(t, err)
+
+	var channel bus.Channel[int] = NewBusChannel[int](ps, nil)
+	var mx sync.Mutex
+	var total int
+	sub := channel.Subscribe(ctx, func(ctx context.Context, notification int) error {
+		mx.Lock()
+		defer mx.Unlock()
+		total += notification
+		return nil
+	})
+
+	require.NoError(t, channel.Publish(ctx, 1))
+	sub.Unsubscribe()
+	require.NoError(t, channel.Publish(ctx, 2))
+
+	mx.Lock()
+	defer mx.Unlock()
+	assert.Equal(t, 1, total)
+}
+
+func TestBusChannel_Close(t *testing.T) {
+	ctx := context.Background()
+	ps, err := newTestPubSub[int]()
+	require.NoError(t, err)
+
+	var mx sync.Mutex
+	var owned, foreign int
+	ps.SubscribeHandlerFunc(ctx, func(ctx context.Context, event *Event[int]) {
+		mx.Lock()
+		defer mx.Unlock()
+		foreign += event.Entity()
+	})
+
+	channel := NewBusChannel[int](ps, nil)
+	channel.Subscribe(ctx, func(ctx context.Context, notification int) error {
+		mx.Lock()
+		defer mx.Unlock()
+		owned += notification
+		return nil
+	})
+	channel.Close()
+
+	assert.ErrorIs(t, channel.Publish(ctx, 1), bus.ErrChannelClosed)
+	require.NoError(t, ps.Publish(ctx, 2).Wait())
+
+	mx.Lock()
+	defer mx.Unlock()
+	assert.Equal(t, 0, owned)
+	assert.Equal(t, 2, foreign)
+}
@@ -64,7 +64,7 @@ func (that *Exporters[T]) Export(ctx context.Context, event *Event[T]) {
 		return
 	}
 
-	event.Capture(len(that.exporters))
+	event.Capture(len(exporters))
 	for _, exporter := range exporters {
 		go func(exporter Exporter) {
 			defer event.Release()
@@ -0,0 +1,44 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/Adverax/core/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type exporterMock struct {
+	accept bool
+}
+
+func (that *exporterMock) CanExport(ctx context.Context, maker, subject string) bool {
+	return that.accept
+}
+
+func (that *exporterMock) Export(ctx context.Context, subject string, entity json.RawMessage) {}
+
+func TestExporters_Export(t *testing.T) {
+	ctx := context.Background()
+	exporters := NewExporters[int]()
+	exporters.Attach(&exporterMock{accept: true})
+	exporters.Attach(&exporterMock{accept: false})
+
+	wg := &waitGroup{chain: dummyObserver, ctx: ctx}
+	exporters.Export(ctx, &Event[int]{ctx: ctx, observer: wg, subject: "test", entity: 1})
+
+	// only the selected exporters are captured, so the event is released
+	done := make(chan struct{})
+	go func() {
+		wg.WaitGroup.Wait()
+		close(done)
+	}()
+	assert.Eventually(t, func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+}
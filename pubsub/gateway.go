@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/Adverax/core"
 	"github.com/Adverax/core/json"
+	"github.com/Adverax/core/trace"
 	"reflect"
 )
 
@@ -21,6 +22,22 @@ type Publisher interface {
 	Publish(ctx context.Context, subject string, entity json.RawMessage)
 }
 
+// Headers are metadata of the exported event, like the trace context.
+type Headers map[string]string
+
+func (that Headers) Get(key string) string {
+	return that[key]
+}
+
+func (that Headers) Set(key, value string) {
+	that[key] = value
+}
+
+// HeadersPublisher is implemented by the publishers, that transfer headers along with the event.
+type HeadersPublisher interface {
+	PublishWithHeaders(ctx context.Context, subject string, entity json.RawMessage, headers Headers)
+}
+
 type Gateway struct {
 	ps     map[string]Pin
 	pub    Publisher
@@ -41,15 +58,25 @@ func (that *Gateway) Import(
 	return ps.Import(ctx, that.id, entity)
 }
 
+// ImportWithHeaders imports the event, restoring the trace context from the headers.
+func (that *Gateway) ImportWithHeaders(
+	ctx context.Context,
+	subject string,
+	entity json.RawMessage,
+	headers Headers,
+) error {
+	return that.Import(trace.Extract(ctx, headers), subject, entity)
+}
+
 func (that *Gateway) CanExport(
 	ctx context.Context,
 	maker, subject string,
 ) bool {
-	if maker == that.id {
+	if maker == that.id || that.pub == nil {
 		return false
 	}
 
-	return that.filter.IsMatch(subject)
+	return that.filter == nil || that.filter.IsMatch(subject)
 }
 
 func (that *Gateway) Export(
@@ -57,14 +84,27 @@ func (that *Gateway) Export(
 	subject string,
 	entity json.RawMessage,
 ) {
+	if pub, ok := that.pub.(HeadersPublisher); ok {
+		headers := make(Headers)
+		trace.Inject(ctx, headers)
+		pub.PublishWithHeaders(ctx, subject, entity, headers)
+		return
+	}
+
 	that.pub.Publish(ctx, subject, entity)
 }
 
 func NewGateway(bus interface{}) *Gateway {
+	return NewExportGateway(bus, nil, nil)
+}
+
+// NewExportGateway makes gateway, that also exports events of the bus matched by the filter to the publisher.
+// Nil filter matches all subjects.
+func NewExportGateway(bus interface{}, pub Publisher, filter MatchFilter) *Gateway {
 	ps := make(map[string]Pin)
 	collectPins(bus, ps)
 
-	gateway := &Gateway{id: core.NewGUID(), ps: ps}
+	gateway := &Gateway{id: core.NewGUID(), ps: ps, pub: pub, filter: filter}
 	for _, pin := range ps {
 		pin.Attach(gateway)
 	}
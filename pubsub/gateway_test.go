@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/Adverax/core/generic"
 	"github.com/Adverax/core/json"
+	"github.com/Adverax/core/trace"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -56,3 +57,55 @@ func TestBus(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+type exportedEvent struct {
+	subject string
+	entity  json.RawMessage
+	headers Headers
+}
+
+type headersPublisher struct {
+	events chan exportedEvent
+}
+
+func (that *headersPublisher) Publish(ctx context.Context, subject string, entity json.RawMessage) {
+	that.PublishWithHeaders(ctx, subject, entity, nil)
+}
+
+func (that *headersPublisher) PublishWithHeaders(
+	ctx context.Context,
+	subject string,
+	entity json.RawMessage,
+	headers Headers,
+) {
+	that.events <- exportedEvent{subject: subject, entity: entity, headers: headers}
+}
+
+func TestGateway_TraceContext(t *testing.T) {
+	bus := &ReceiptBus{
+		OnCreated: generic.Must(NewBuilder[*Receipt]().Subject("receipt.inserted").Build()),
+	}
+	pub := &headersPublisher{events: make(chan exportedEvent, 1)}
+	gateway := NewExportGateway(bus, pub, nil)
+
+	sc := trace.New()
+	ctx := trace.NewContext(context.Background(), sc)
+	require.NoError(t, bus.OnCreated.Publish(ctx, &Receipt{Id: "1"}).Wait())
+
+	exported := <-pub.events
+	require.Equal(t, "receipt.inserted", exported.subject)
+	require.Equal(t, sc.Traceparent(), exported.headers[trace.HeaderTraceparent])
+
+	imported := make(chan trace.SpanContext, 1)
+	bus.OnCreated.SubscribeHandlerFunc(
+		context.Background(),
+		func(ctx context.Context, event *Event[*Receipt]) {
+			sc, _ := trace.FromContext(event.Context())
+			imported <- sc
+		},
+	)
+
+	err := gateway.ImportWithHeaders(context.Background(), exported.subject, exported.entity, exported.headers)
+	require.NoError(t, err)
+	require.Equal(t, sc, <-imported)
+}
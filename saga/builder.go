@@ -0,0 +1,118 @@
+package saga
+
+import (
+	"fmt"
+	"github.com/Adverax/core"
+	"github.com/Adverax/core/log"
+	"time"
+)
+
+const defaultStaleTimeout = 10 * time.Minute
+
+type Builder[T any] struct {
+	*core.Builder
+	saga *Saga[T]
+}
+
+func NewBuilder[T any]() *Builder[T] {
+	return &Builder[T]{
+		Builder: core.NewBuilder("Saga"),
+		saga: &Saga[T]{
+			locks: newLocks(),
+		},
+	}
+}
+
+func (that *Builder[T]) Name(name string) *Builder[T] {
+	that.saga.name = name
+	return that
+}
+
+// Step appends step, that completes as soon as action is done.
+func (that *Builder[T]) Step(name string, action, compensate Action[T]) *Builder[T] {
+	return that.step(name, action, compensate, false)
+}
+
+// AwaitStep appends step, that completes only after the saga is resumed by external event.
+func (that *Builder[T]) AwaitStep(name string, action, compensate Action[T]) *Builder[T] {
+	return that.step(name, action, compensate, true)
+}
+
+func (that *Builder[T]) step(name string, action, compensate Action[T], await bool) *Builder[T] {
+	if action == nil {
+		that.AddError(fmt.Errorf("step %s: %w", name, ErrFieldActionIsRequired))
+		return that
+	}
+
+	that.saga.steps = append(that.saga.steps, &step[T]{
+		name:       name,
+		action:     action,
+		compensate: compensate,
+		await:      await,
+	})
+	return that
+}
+
+func (that *Builder[T]) Store(store Store) *Builder[T] {
+	that.saga.store = store
+	return that
+}
+
+// Timeout limits total duration of the saga.
+func (that *Builder[T]) Timeout(timeout time.Duration) *Builder[T] {
+	that.saga.timeout = timeout
+	return that
+}
+
+// StaleTimeout is the duration, after which the running saga without updates is considered interrupted
+// and is compensated by Expire. It must exceed duration of the longest step. Default is 10 minutes.
+func (that *Builder[T]) StaleTimeout(timeout time.Duration) *Builder[T] {
+	that.saga.stale = timeout
+	return that
+}
+
+func (that *Builder[T]) Logger(logger log.Logger) *Builder[T] {
+	that.saga.logger = logger
+	return that
+}
+
+func (that *Builder[T]) Build() (*Saga[T], error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+
+	if err := that.updateDefaultFields(); err != nil {
+		return nil, err
+	}
+
+	return that.saga, nil
+}
+
+func (that *Builder[T]) checkRequiredFields() error {
+	that.RequiredField(that.saga.name, ErrFieldNameIsRequired)
+	that.RequiredField(that.saga.steps, ErrFieldStepsAreRequired)
+
+	return that.ResError()
+}
+
+func (that *Builder[T]) updateDefaultFields() error {
+	if that.saga.store == nil {
+		that.saga.store = NewMemoryStore()
+	}
+
+	if that.saga.logger == nil {
+		that.saga.logger = log.NewDummyLogger()
+	}
+
+	if that.saga.stale <= 0 {
+		that.saga.stale = defaultStaleTimeout
+	}
+
+	return that.ResError()
+}
+
+var (
+	ErrFieldNameIsRequired   = fmt.Errorf("Field 'name' is required")
+	ErrFieldStepsAreRequired = fmt.Errorf("Field 'steps' are required")
+	ErrFieldActionIsRequired = fmt.Errorf("Field 'action' is required")
+)
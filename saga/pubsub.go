@@ -0,0 +1,57 @@
+package saga
+
+import (
+	"context"
+	"github.com/Adverax/core/pubsub"
+)
+
+// Correlator extracts identifier of the saga from the event.
+type Correlator[E any] func(event E) string
+
+// Reducer applies event to the data of the awaiting saga.
+// Returned error rejects the awaiting step.
+type Reducer[T, E any] func(ctx context.Context, event E, data *T) error
+
+// Listen starts new saga for every event of the pubsub.
+func (that *Saga[T]) Listen(ctx context.Context, ps *pubsub.PubSub[T]) pubsub.Subscriber[T] {
+	return ps.SubscribeHandlerFunc(
+		ctx,
+		func(ctx context.Context, event *pubsub.Event[T]) {
+			id, err := that.Start(ctx, event.Entity())
+			if err != nil {
+				that.logger.WithError(ctx, err).Errorf(ctx, "saga %s (%s) failed", that.name, id)
+			}
+		},
+	)
+}
+
+// ResumeOn advances awaiting sagas by events of the pubsub.
+func ResumeOn[T, E any](
+	ctx context.Context,
+	saga *Saga[T],
+	ps *pubsub.PubSub[E],
+	correlate Correlator[E],
+	reduce Reducer[T, E],
+) pubsub.Subscriber[E] {
+	return ps.SubscribeHandlerFunc(
+		ctx,
+		func(ctx context.Context, event *pubsub.Event[E]) {
+			entity := event.Entity()
+			id := correlate(entity)
+			if id == "" {
+				return
+			}
+
+			err := saga.Resume(
+				ctx,
+				id,
+				func(ctx context.Context, data *T) error {
+					return reduce(ctx, entity, data)
+				},
+			)
+			if err != nil {
+				saga.logger.WithError(ctx, err).Errorf(ctx, "saga %s (%s) failed", saga.name, id)
+			}
+		},
+	)
+}
@@ -0,0 +1,332 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Adverax/core"
+	"github.com/Adverax/core/json"
+	"github.com/Adverax/core/log"
+	"sync"
+	"time"
+)
+
+type contextKeyType int
+
+const contextKeyID contextKeyType = 0
+
+// ID returns identifier of the saga instance, that executes the action.
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKeyID).(string)
+	return id
+}
+
+// Action is a local transaction of the step or its compensation.
+type Action[T any] func(ctx context.Context, data *T) error
+
+type step[T any] struct {
+	name       string
+	action     Action[T]
+	compensate Action[T]
+	await      bool
+}
+
+// Saga coordinates the sequence of steps. When any step fails or saga
+// is timed out, compensations of already completed steps are executed in reverse order.
+type Saga[T any] struct {
+	name    string
+	steps   []*step[T]
+	store   Store
+	timeout time.Duration
+	stale   time.Duration
+	logger  log.Logger
+	locks   *locks
+}
+
+func (that *Saga[T]) Name() string {
+	return that.name
+}
+
+// Start creates new instance of the saga and executes it until the first awaiting step.
+func (that *Saga[T]) Start(ctx context.Context, data T) (string, error) {
+	now := time.Now()
+	rec := &Record{
+		ID:     core.NewGUID(),
+		Saga:   that.name,
+		Status: StatusRunning,
+	}
+	if that.timeout > 0 {
+		rec.Deadline = now.Add(that.timeout)
+	}
+
+	unlock := that.locks.lock(rec.ID)
+	defer unlock()
+
+	// record is saved before the first step, so the saga interrupted by the crash can be recovered by Expire
+	ctx = context.WithValue(ctx, contextKeyID, rec.ID)
+	if err := that.save(ctx, rec, &data); err != nil {
+		return "", err
+	}
+
+	return rec.ID, that.run(ctx, rec, &data)
+}
+
+// Resume completes the awaiting step of the saga and continues execution.
+// Error of the update rejects the step and starts compensation.
+func (that *Saga[T]) Resume(ctx context.Context, id string, update Action[T]) error {
+	unlock := that.locks.lock(id)
+	defer unlock()
+
+	ctx = context.WithValue(ctx, contextKeyID, id)
+	rec, data, err := that.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rec.Status != StatusWaiting {
+		return ErrSagaNotWaiting
+	}
+
+	if update != nil {
+		if err := update(ctx, data); err != nil {
+			return that.compensate(ctx, rec, data, err)
+		}
+	}
+
+	rec.Step++
+	rec.Status = StatusRunning
+	return that.run(ctx, rec, data)
+}
+
+// Reject fails the awaiting step of the saga and starts compensation.
+func (that *Saga[T]) Reject(ctx context.Context, id string, cause error) error {
+	unlock := that.locks.lock(id)
+	defer unlock()
+
+	ctx = context.WithValue(ctx, contextKeyID, id)
+	rec, data, err := that.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rec.Status != StatusWaiting {
+		return ErrSagaNotWaiting
+	}
+
+	return that.compensate(ctx, rec, data, cause)
+}
+
+// Expire compensates all active sagas with exceeded deadline and running sagas,
+// that are not updated during the stale timeout (see Builder.StaleTimeout),
+// and continues compensations, that were interrupted.
+func (that *Saga[T]) Expire(ctx context.Context) error {
+	now := time.Now()
+	recs, err := that.store.Expired(ctx, that.name, now)
+	if err != nil {
+		return err
+	}
+
+	errs := core.NewErrors()
+	for _, r := range recs {
+		if err := that.expire(ctx, r.ID, now); err != nil && !errors.Is(err, ErrTimeout) && !errors.Is(err, ErrInterrupted) {
+			errs.AddError(err)
+		}
+	}
+
+	return errs.ResError()
+}
+
+func (that *Saga[T]) expire(ctx context.Context, id string, now time.Time) error {
+	unlock := that.locks.lock(id)
+	defer unlock()
+
+	ctx = context.WithValue(ctx, contextKeyID, id)
+	rec, data, err := that.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rec.Status.IsFinal() {
+		return nil
+	}
+
+	if rec.Status == StatusCompensating {
+		// compensation was interrupted, so it is continued from the remaining steps
+		cause := errors.New(rec.Error)
+		if err := that.compensate(ctx, rec, data, cause); err != cause {
+			return err
+		}
+		return nil
+	}
+
+	if !rec.Deadline.IsZero() && rec.Deadline.Before(now) {
+		return that.compensate(ctx, rec, data, ErrTimeout)
+	}
+
+	if rec.Status == StatusRunning && rec.UpdatedAt.Before(now.Add(-that.stale)) {
+		// the step was interrupted, so the completed steps are compensated
+		return that.compensate(ctx, rec, data, ErrInterrupted)
+	}
+
+	return nil
+}
+
+// Watch periodically expires timed out sagas until context is done.
+func (that *Saga[T]) Watch(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := that.Expire(ctx); err != nil {
+					that.logger.WithError(ctx, err).Errorf(ctx, "saga %s: expiration failed", that.name)
+				}
+			}
+		}
+	}()
+}
+
+// State returns persistent state of the saga instance.
+func (that *Saga[T]) State(ctx context.Context, id string) (*Record, *T, error) {
+	return that.load(ctx, id)
+}
+
+func (that *Saga[T]) run(ctx context.Context, rec *Record, data *T) error {
+	for rec.Step < len(that.steps) {
+		s := that.steps[rec.Step]
+		if err := s.action(ctx, data); err != nil {
+			return that.compensate(ctx, rec, data, fmt.Errorf("step %s: %w", s.name, err))
+		}
+
+		if s.await {
+			rec.Status = StatusWaiting
+			return that.save(ctx, rec, data)
+		}
+
+		rec.Step++
+		if err := that.save(ctx, rec, data); err != nil {
+			return err
+		}
+	}
+
+	rec.Status = StatusCompleted
+	return that.save(ctx, rec, data)
+}
+
+func (that *Saga[T]) compensate(ctx context.Context, rec *Record, data *T, cause error) error {
+	if rec.Status == StatusWaiting {
+		// action of the awaiting step is already executed
+		rec.Step++
+	}
+	rec.Status = StatusCompensating
+	rec.Error = cause.Error()
+	if err := that.save(ctx, rec, data); err != nil {
+		return core.NewErrors(cause, err)
+	}
+
+	for rec.Step > 0 {
+		s := that.steps[rec.Step-1]
+		if s.compensate != nil {
+			if err := s.compensate(ctx, data); err != nil {
+				err = fmt.Errorf("compensation %s: %w", s.name, err)
+				rec.Status = StatusFailed
+				rec.Error = core.NewErrors(cause, err).Error()
+				return core.Check(cause, err, that.save(ctx, rec, data))
+			}
+		}
+
+		rec.Step--
+		if err := that.save(ctx, rec, data); err != nil {
+			return core.NewErrors(cause, err)
+		}
+	}
+
+	rec.Status = StatusCompensated
+	if err := that.save(ctx, rec, data); err != nil {
+		return core.NewErrors(cause, err)
+	}
+
+	return cause
+}
+
+func (that *Saga[T]) save(ctx context.Context, rec *Record, data *T) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	rec.Data = raw
+	rec.UpdatedAt = time.Now()
+	return that.store.Save(ctx, rec)
+}
+
+func (that *Saga[T]) load(ctx context.Context, id string) (*Record, *T, error) {
+	rec, err := that.store.Load(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rec.Saga != that.name {
+		return nil, nil, ErrSagaNotFound
+	}
+
+	data := new(T)
+	if err := json.Unmarshal(rec.Data, data); err != nil {
+		return nil, nil, err
+	}
+
+	return rec, data, nil
+}
+
+// locks serializes transitions of the same saga instance.
+type locks struct {
+	mx    sync.Mutex
+	items map[string]*lock
+}
+
+type lock struct {
+	sync.Mutex
+	refs int
+}
+
+func newLocks() *locks {
+	return &locks{
+		items: make(map[string]*lock),
+	}
+}
+
+func (that *locks) lock(id string) func() {
+	that.mx.Lock()
+	l, ok := that.items[id]
+	if !ok {
+		l = &lock{}
+		that.items[id] = l
+	}
+	l.refs++
+	that.mx.Unlock()
+
+	l.Lock()
+
+	return func() {
+		l.Unlock()
+
+		that.mx.Lock()
+		defer that.mx.Unlock()
+
+		l.refs--
+		if l.refs == 0 {
+			delete(that.items, id)
+		}
+	}
+}
+
+var (
+	ErrSagaNotFound   = errors.New("saga not found")
+	ErrSagaNotWaiting = errors.New("saga is not waiting")
+	ErrTimeout        = errors.New("saga timed out")
+	ErrInterrupted    = errors.New("saga interrupted")
+)
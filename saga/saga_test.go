@@ -0,0 +1,226 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"github.com/Adverax/core/generic"
+	"github.com/Adverax/core/json"
+	"github.com/Adverax/core/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type order struct {
+	ID      string   `json:"id"`
+	Paid    bool     `json:"paid"`
+	Journal []string `json:"journal"`
+}
+
+type payment struct {
+	SagaID string `json:"saga_id"`
+	Ok     bool   `json:"ok"`
+}
+
+func record(name string) Action[order] {
+	return func(ctx context.Context, data *order) error {
+		data.Journal = append(data.Journal, name)
+		return nil
+	}
+}
+
+func newOrderSaga(t *testing.T, pay Action[order]) *Saga[order] {
+	s, err := NewBuilder[order]().
+		Name("order").
+		Step("reserve", record("reserve"), record("release")).
+		AwaitStep("pay", pay, record("refund")).
+		Step("ship", record("ship"), nil).
+		Timeout(time.Hour).
+		Build()
+	require.NoError(t, err)
+	return s
+}
+
+func TestSaga_Completed(t *testing.T) {
+	ctx := context.Background()
+	s := newOrderSaga(t, record("pay"))
+
+	id, err := s.Start(ctx, order{ID: "1"})
+	require.NoError(t, err)
+
+	rec, data, err := s.State(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusWaiting, rec.Status)
+	assert.Equal(t, []string{"reserve", "pay"}, data.Journal)
+
+	err = s.Resume(ctx, id, func(ctx context.Context, data *order) error {
+		data.Paid = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	rec, data, err = s.State(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, rec.Status)
+	assert.True(t, data.Paid)
+	assert.Equal(t, []string{"reserve", "pay", "ship"}, data.Journal)
+	assert.ErrorIs(t, s.Resume(ctx, id, nil), ErrSagaNotWaiting)
+}
+
+func TestSaga_Compensated(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("no money")
+	s := newOrderSaga(t, func(ctx context.Context, data *order) error {
+		return failure
+	})
+
+	id, err := s.Start(ctx, order{ID: "1"})
+	require.ErrorIs(t, err, failure)
+
+	rec, data, err := s.State(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompensated, rec.Status)
+	assert.Equal(t, []string{"reserve", "release"}, data.Journal)
+}
+
+func TestSaga_Timeout(t *testing.T) {
+	ctx := context.Background()
+	s := newOrderSaga(t, record("pay"))
+	s.timeout = time.Nanosecond
+
+	id, err := s.Start(ctx, order{ID: "1"})
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	require.NoError(t, s.Expire(ctx))
+
+	rec, data, err := s.State(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompensated, rec.Status)
+	assert.Equal(t, ErrTimeout.Error(), rec.Error)
+	assert.Equal(t, []string{"reserve", "pay", "refund", "release"}, data.Journal)
+}
+
+func TestSaga_Rejected(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("declined")
+	s := newOrderSaga(t, record("pay"))
+
+	id, err := s.Start(ctx, order{ID: "1"})
+	require.NoError(t, err)
+	require.ErrorIs(t, s.Reject(ctx, id, failure), failure)
+
+	rec, data, err := s.State(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompensated, rec.Status)
+	assert.Equal(t, []string{"reserve", "pay", "refund", "release"}, data.Journal)
+}
+
+func TestSaga_InterruptedCompensation(t *testing.T) {
+	ctx := context.Background()
+	s := newOrderSaga(t, record("pay"))
+	s.timeout = 0
+
+	// compensation of the first step was interrupted after the refund
+	require.NoError(t, s.save(ctx, &Record{
+		ID:     "1",
+		Saga:   "order",
+		Status: StatusCompensating,
+		Step:   1,
+		Error:  "declined",
+	}, &order{ID: "1", Journal: []string{"reserve", "pay", "refund"}}))
+
+	require.NoError(t, s.Expire(ctx))
+
+	rec, data, err := s.State(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompensated, rec.Status)
+	assert.Equal(t, "declined", rec.Error)
+	assert.Equal(t, []string{"reserve", "pay", "refund", "release"}, data.Journal)
+}
+
+func TestSaga_SavedBeforeFirstStep(t *testing.T) {
+	ctx := context.Background()
+	var status Status
+	var s *Saga[order]
+	s, err := NewBuilder[order]().
+		Name("order").
+		Step("check", func(ctx context.Context, data *order) error {
+			rec, _, err := s.State(ctx, ID(ctx))
+			if err != nil {
+				return err
+			}
+			status = rec.Status
+			return nil
+		}, nil).
+		Build()
+	require.NoError(t, err)
+
+	_, err = s.Start(ctx, order{ID: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, status)
+}
+
+func TestSaga_InterruptedStep(t *testing.T) {
+	ctx := context.Background()
+	s := newOrderSaga(t, record("pay"))
+	s.timeout = 0
+
+	// the process crashed during the payment
+	data, err := json.Marshal(&order{ID: "1", Journal: []string{"reserve", "pay"}})
+	require.NoError(t, err)
+	running := &Record{ID: "1", Saga: "order", Status: StatusRunning, Step: 1, Data: data, UpdatedAt: time.Now()}
+	require.NoError(t, s.store.Save(ctx, running))
+	stale := &Record{ID: "2", Saga: "order", Status: StatusRunning, Step: 1, Data: data, UpdatedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, s.store.Save(ctx, stale))
+
+	require.NoError(t, s.Expire(ctx))
+
+	rec, _, err := s.State(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, rec.Status)
+
+	rec, state, err := s.State(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompensated, rec.Status)
+	assert.Equal(t, ErrInterrupted.Error(), rec.Error)
+	assert.Equal(t, []string{"reserve", "pay", "release"}, state.Journal)
+}
+
+func TestSaga_PubSub(t *testing.T) {
+	ctx := context.Background()
+	orders := generic.Must(pubsub.NewBuilder[order]().Subject("order.created").Build())
+	payments := generic.Must(pubsub.NewBuilder[payment]().Subject("payment.completed").Build())
+
+	ids := make(chan string, 1)
+	s := newOrderSaga(t, record("pay"))
+	s.steps[1].action = func(ctx context.Context, data *order) error {
+		ids <- ID(ctx)
+		return nil
+	}
+	s.Listen(ctx, orders)
+	ResumeOn[order, payment](
+		ctx,
+		s,
+		payments,
+		func(event payment) string {
+			return event.SagaID
+		},
+		func(ctx context.Context, event payment, data *order) error {
+			if !event.Ok {
+				return errors.New("payment rejected")
+			}
+			data.Paid = true
+			return nil
+		},
+	)
+
+	require.NoError(t, orders.Publish(ctx, order{ID: "1"}).Wait())
+	id := <-ids
+	require.NoError(t, payments.Publish(ctx, payment{SagaID: id, Ok: true}).Wait())
+
+	rec, data, err := s.State(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, rec.Status)
+	assert.True(t, data.Paid)
+}
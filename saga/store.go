@@ -0,0 +1,101 @@
+package saga
+
+import (
+	"context"
+	"github.com/Adverax/core/json"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusRunning      Status = "running"
+	StatusWaiting      Status = "waiting"
+	StatusCompleted    Status = "completed"
+	StatusCompensating Status = "compensating"
+	StatusCompensated  Status = "compensated"
+	StatusFailed       Status = "failed"
+)
+
+// IsFinal returns true if saga can't be advanced anymore.
+func (that Status) IsFinal() bool {
+	switch that {
+	case StatusCompleted, StatusCompensated, StatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Record is a persistent state of the saga instance.
+type Record struct {
+	ID        string          `json:"id"`
+	Saga      string          `json:"saga"`
+	Step      int             `json:"step"`
+	Status    Status          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	Error     string          `json:"error,omitempty"`
+	Deadline  time.Time       `json:"deadline"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type Store interface {
+	Save(ctx context.Context, record *Record) error
+	Load(ctx context.Context, id string) (*Record, error)
+	// Expired returns active records of the saga with deadline before now
+	// and all running and compensating records.
+	Expired(ctx context.Context, saga string, now time.Time) ([]*Record, error)
+}
+
+type MemoryStore struct {
+	mx      sync.Mutex
+	records map[string]*Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]*Record),
+	}
+}
+
+func (that *MemoryStore) Save(ctx context.Context, record *Record) error {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	r := *record
+	that.records[record.ID] = &r
+	return nil
+}
+
+func (that *MemoryStore) Load(ctx context.Context, id string) (*Record, error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	record, ok := that.records[id]
+	if !ok {
+		return nil, ErrSagaNotFound
+	}
+
+	r := *record
+	return &r, nil
+}
+
+func (that *MemoryStore) Expired(ctx context.Context, saga string, now time.Time) ([]*Record, error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	var res []*Record
+	for _, record := range that.records {
+		if record.Saga != saga || record.Status.IsFinal() {
+			continue
+		}
+		expired := !record.Deadline.IsZero() && record.Deadline.Before(now)
+		if expired || record.Status == StatusRunning || record.Status == StatusCompensating {
+			r := *record
+			res = append(res, &r)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// Calendar defines blackout periods, when the ticks of the BlackoutSchedule are skipped.
+type Calendar interface {
+	Excludes(t time.Time) bool
+}
+
+// HolidayCalendar excludes whole days in its location.
+type HolidayCalendar struct {
+	mx       sync.RWMutex
+	location *time.Location
+	days     map[string]struct{}
+}
+
+func NewHolidayCalendar(location *time.Location, days ...time.Time) *HolidayCalendar {
+	c := &HolidayCalendar{
+		location: location,
+		days:     make(map[string]struct{}),
+	}
+
+	for _, day := range days {
+		c.Add(day)
+	}
+
+	return c
+}
+
+// Add excludes the day of the time t.
+func (that *HolidayCalendar) Add(t time.Time) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.days[that.key(t)] = struct{}{}
+}
+
+func (that *HolidayCalendar) Remove(t time.Time) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	delete(that.days, that.key(t))
+}
+
+func (that *HolidayCalendar) Excludes(t time.Time) bool {
+	that.mx.RLock()
+	defer that.mx.RUnlock()
+
+	_, ok := that.days[that.key(t)]
+	return ok
+}
+
+func (that *HolidayCalendar) key(t time.Time) string {
+	return t.In(that.location).Format("2006-01-02")
+}
+
+// Window is a half-open interval [From, To) of time.
+type Window struct {
+	From time.Time
+	To   time.Time
+}
+
+func (that Window) Contains(t time.Time) bool {
+	return !t.Before(that.From) && t.Before(that.To)
+}
+
+// WindowCalendar excludes maintenance windows.
+type WindowCalendar struct {
+	mx      sync.RWMutex
+	windows []Window
+}
+
+func NewWindowCalendar(windows ...Window) *WindowCalendar {
+	return &WindowCalendar{
+		windows: windows,
+	}
+}
+
+func (that *WindowCalendar) Add(window Window) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.windows = append(that.windows, window)
+}
+
+func (that *WindowCalendar) Excludes(t time.Time) bool {
+	that.mx.RLock()
+	defer that.mx.RUnlock()
+
+	for _, w := range that.windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Calendars excludes time, that is excluded by any of the calendars.
+type Calendars []Calendar
+
+func (that Calendars) Excludes(t time.Time) bool {
+	for _, c := range that {
+		if c.Excludes(t) {
+			return true
+		}
+	}
+
+	return false
+}
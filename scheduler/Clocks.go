@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is a source of time for the scheduler.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// RealClock is a Clock backed by the time package.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{timer: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (that *realTimer) C() <-chan time.Time {
+	return that.timer.C
+}
+
+func (that *realTimer) Stop() bool {
+	return that.timer.Stop()
+}
+
+func (that *realTimer) Reset(d time.Duration) bool {
+	return that.timer.Reset(d)
+}
+
+// FakeClock is a Clock, that is moved forward manually by Advance.
+type FakeClock struct {
+	mx      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mx)
+	return c
+}
+
+func (that *FakeClock) Now() time.Time {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	return that.now
+}
+
+func (that *FakeClock) After(d time.Duration) <-chan time.Time {
+	return that.NewTimer(d).C()
+}
+
+func (that *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{
+		clock: that,
+		ch:    make(chan time.Time, 1),
+	}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward and fires timers, whose deadlines are reached, in order of deadlines.
+func (that *FakeClock) Advance(d time.Duration) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.now = that.now.Add(d)
+
+	var fired []*fakeTimer
+	waiters := that.waiters[:0]
+	for _, t := range that.waiters {
+		if t.deadline.After(that.now) {
+			waiters = append(waiters, t)
+		} else {
+			fired = append(fired, t)
+		}
+	}
+	that.waiters = waiters
+
+	sort.SliceStable(fired, func(i, j int) bool {
+		return fired[i].deadline.Before(fired[j].deadline)
+	})
+	for _, t := range fired {
+		t.fire(that.now)
+	}
+
+	that.cond.Broadcast()
+}
+
+// BlockUntil waits until there are at least n active timers, e.g. the scheduler is waiting for the next tick.
+func (that *FakeClock) BlockUntil(n int) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	for len(that.waiters) < n {
+		that.cond.Wait()
+	}
+}
+
+func (that *FakeClock) remove(t *fakeTimer) bool {
+	for i, w := range that.waiters {
+		if w == t {
+			that.waiters = append(that.waiters[:i], that.waiters[i+1:]...)
+			that.cond.Broadcast()
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (that *fakeTimer) C() <-chan time.Time {
+	return that.ch
+}
+
+func (that *fakeTimer) Stop() bool {
+	that.clock.mx.Lock()
+	defer that.clock.mx.Unlock()
+
+	return that.clock.remove(that)
+}
+
+func (that *fakeTimer) Reset(d time.Duration) bool {
+	c := that.clock
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	active := c.remove(that)
+	that.deadline = c.now.Add(d)
+	if d <= 0 {
+		that.fire(c.now)
+		return active
+	}
+
+	c.waiters = append(c.waiters, that)
+	c.cond.Broadcast()
+	return active
+}
+
+func (that *fakeTimer) fire(now time.Time) {
+	select {
+	case that.ch <- now:
+	default:
+	}
+}
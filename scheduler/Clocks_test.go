@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	timer1 := clock.NewTimer(10 * time.Millisecond)
+	timer2 := clock.NewTimer(20 * time.Millisecond)
+	timer3 := clock.NewTimer(30 * time.Millisecond)
+	assert.True(t, timer3.Stop())
+	clock.BlockUntil(2)
+
+	clock.Advance(15 * time.Millisecond)
+	assert.Equal(t, start.Add(15*time.Millisecond), clock.Now())
+	assert.Equal(t, start.Add(15*time.Millisecond), <-timer1.C())
+	select {
+	case <-timer2.C():
+		t.Fatal("timer is fired before deadline")
+	default:
+	}
+
+	assert.True(t, timer2.Reset(time.Millisecond))
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, start.Add(16*time.Millisecond), <-timer2.C())
+	assert.False(t, timer2.Stop())
+
+	select {
+	case <-timer3.C():
+		t.Fatal("stopped timer is fired")
+	case <-clock.After(0):
+	}
+}
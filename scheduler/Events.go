@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,10 +15,9 @@ type Event interface {
 }
 
 type Action struct {
-	schedule   Schedule
-	name       string
-	handler    func(ctx context.Context) error
-	executedAt time.Time
+	schedule Schedule
+	name     string
+	handler  func(ctx context.Context) error
 }
 
 func (a *Action) String() string {
@@ -28,8 +28,12 @@ func (a *Action) Name() string {
 	return a.name
 }
 
+func (a *Action) Schedule() Schedule {
+	return a.schedule
+}
+
 func (a *Action) NextTick(now time.Time) time.Time {
-	return a.schedule.NextTick(a.executedAt)
+	return a.schedule.NextTick(now)
 }
 
 func (a *Action) Trigger(ctx context.Context) error {
@@ -38,19 +42,25 @@ func (a *Action) Trigger(ctx context.Context) error {
 
 func NewAction(name string, schedule Schedule, handler func(ctx context.Context) error) *Action {
 	return &Action{
-		schedule:   schedule,
-		name:       name,
-		handler:    handler,
-		executedAt: time.Now(),
+		schedule: schedule,
+		name:     name,
+		handler:  handler,
 	}
 }
 
+// Asynchronous is implemented by events, that are executed by the scheduler in the separate routine,
+// so they don't delay other events.
+type Asynchronous interface {
+	// Execute runs the event synchronously.
+	Execute(ctx context.Context) error
+}
+
 type AsyncAction struct {
-	schedule   Schedule
-	name       string
-	handler    func(ctx context.Context) error
-	active     bool
-	executedAt time.Time
+	schedule Schedule
+	name     string
+	handler  func(ctx context.Context) error
+	logger   Logger
+	active   int32
 }
 
 func (a *AsyncAction) String() string {
@@ -61,25 +71,35 @@ func (a *AsyncAction) Name() string {
 	return a.name
 }
 
+func (a *AsyncAction) Schedule() Schedule {
+	return a.schedule
+}
+
 func (a *AsyncAction) NextTick(now time.Time) time.Time {
 	return a.schedule.NextTick(now)
 }
 
+// SetLogger sets logger for errors of the executions started by Trigger.
+func (a *AsyncAction) SetLogger(logger Logger) {
+	a.logger = logger
+}
+
+func (a *AsyncAction) Execute(ctx context.Context) error {
+	return a.handler(ctx)
+}
+
+// Trigger starts execution in the separate routine, unless the previous one is still active.
 func (a *AsyncAction) Trigger(ctx context.Context) error {
-	if a.active {
+	if !atomic.CompareAndSwapInt32(&a.active, 0, 1) {
 		return nil
 	}
 
-	a.active = true
 	go func() {
-		defer func() {
-			a.active = false
-			a.executedAt = time.Now()
-		}()
+		defer atomic.StoreInt32(&a.active, 0)
 
 		err := a.handler(ctx)
 		if err != nil {
-			fmt.Println(err)
+			a.logger.LogError(ctx, err)
 		}
 	}()
 
@@ -88,10 +108,10 @@ func (a *AsyncAction) Trigger(ctx context.Context) error {
 
 func NewAsyncAction(name string, schedule Schedule, handler func(ctx context.Context) error) *AsyncAction {
 	return &AsyncAction{
-		schedule:   schedule,
-		name:       name,
-		handler:    handler,
-		executedAt: time.Now(),
+		schedule: schedule,
+		name:     name,
+		handler:  handler,
+		logger:   dummyLogger{},
 	}
 }
 
@@ -99,6 +119,35 @@ type event struct {
 	Event
 	nextTimestamp int64
 	nextTick      time.Time
+	lastRun       time.Time
+	misfire       MisfirePolicy
+	catchUp       bool
+	lastErr       error
+	running       int
+	paused        bool
+	removed       bool
+	timeout       time.Duration
+	retries       int
+	backoff       Backoff
+	concurrency   ConcurrencyPolicy
+	cancels       map[int]context.CancelFunc
+	history       *history
+	dependencies  []string
+	trigger       TriggerRule
+}
+
+func (e *event) setNextTick(tick time.Time) {
+	e.nextTick = tick
+	e.nextTimestamp = tick.UnixNano()
+}
+
+// execute runs the event synchronously.
+func (e *event) execute(ctx context.Context) error {
+	if a, ok := e.Event.(Asynchronous); ok {
+		return a.Execute(ctx)
+	}
+
+	return e.Trigger(ctx)
 }
 
 func (e *event) String() string {
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const defaultHistorySize = 10
+
+// Execution is a record of the finished execution of the event.
+type Execution struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	Attempts  int
+	Err       error
+}
+
+// Backoff returns delay before the retry with the given number (starting from 1).
+type Backoff func(retry int) time.Duration
+
+// ConstantBackoff waits the same delay before each retry.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles delay before each retry up to the limit.
+func ExponentialBackoff(initial, limit time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		delay := initial
+		for i := 1; i < retry; i++ {
+			delay *= 2
+			if delay >= limit {
+				return limit
+			}
+		}
+		return delay
+	}
+}
+
+// history is a ring of the recent executions.
+type history struct {
+	items []Execution
+	next  int
+	size  int
+}
+
+func newHistory(size int) *history {
+	return &history{size: size}
+}
+
+func (h *history) add(e Execution) {
+	if h.size <= 0 {
+		return
+	}
+
+	if len(h.items) < h.size {
+		h.items = append(h.items, e)
+	} else {
+		h.items[h.next] = e
+	}
+	h.next = (h.next + 1) % h.size
+}
+
+// list returns executions from the oldest to the newest.
+func (h *history) list() []Execution {
+	res := make([]Execution, 0, len(h.items))
+	if len(h.items) < h.size {
+		return append(res, h.items...)
+	}
+
+	res = append(res, h.items[h.next:]...)
+	return append(res, h.items[:h.next]...)
+}
+
+// History returns recent executions of the event from the oldest to the newest.
+func (s *Scheduler) History(name string) ([]Execution, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	ev, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	return ev.history.list(), nil
+}
+
+// run executes the event according to its concurrency, timeout and retry policies.
+// Started is called, when the execution is started, if it is not nil.
+func (s *Scheduler) run(ctx context.Context, event *event, started func()) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mx.Lock()
+	if s.stopped {
+		s.mx.Unlock()
+		return ErrSchedulerStopped
+	}
+	if event.running > 0 {
+		switch event.concurrency {
+		case ConcurrencyForbid:
+			s.mx.Unlock()
+			s.skip(ctx, event, ErrJobRunning)
+			return ErrJobRunning
+		case ConcurrencyReplace:
+			for _, c := range event.cancels {
+				c()
+			}
+		}
+	}
+
+	startedAt := s.clock.Now()
+	event.lastRun = startedAt
+	event.running++
+	s.seq++
+	id := s.seq
+	if event.cancels == nil {
+		event.cancels = make(map[int]context.CancelFunc)
+	}
+	event.cancels[id] = cancel
+	s.cancels[id] = cancel
+	s.executions.Add(1)
+	s.mx.Unlock()
+	defer s.executions.Done()
+
+	if started != nil {
+		started()
+	}
+	attempts, err := s.attempt(ctx, event)
+
+	s.mx.Lock()
+	delete(event.cancels, id)
+	delete(s.cancels, id)
+	event.running--
+	event.lastErr = err
+	event.history.add(Execution{
+		StartedAt: startedAt,
+		Duration:  s.clock.Now().Sub(startedAt),
+		Attempts:  attempts,
+		Err:       err,
+	})
+	if event.running == 0 {
+		s.wake(event)
+	}
+	s.mx.Unlock()
+
+	return err
+}
+
+// attempt executes the event until success or exhaustion of the retries.
+func (s *Scheduler) attempt(ctx context.Context, event *event) (int, error) {
+	for attempt := 1; ; attempt++ {
+		s.emit(ctx, JobEvent{Kind: JobStarted, Job: event.Name(), Attempt: attempt})
+		started := s.clock.Now()
+		err := s.try(ctx, event)
+		retry := err != nil && attempt <= event.retries && ctx.Err() == nil
+
+		e := JobEvent{Kind: JobSucceeded, Job: event.Name(), Attempt: attempt, Duration: s.clock.Now().Sub(started)}
+		if err != nil {
+			e.Kind, e.Err, e.Retry = JobFailed, err, retry
+		}
+		s.emit(ctx, e)
+
+		if !retry {
+			return attempt, err
+		}
+
+		if event.backoff == nil {
+			continue
+		}
+
+		timer := s.clock.NewTimer(event.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C():
+		}
+	}
+}
+
+// try executes the event once within its timeout.
+func (s *Scheduler) try(ctx context.Context, event *event) error {
+	if event.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, event.timeout)
+		defer cancel()
+	}
+
+	return event.execute(ctx)
+}
+
+var (
+	ErrJobRunning       = errors.New("job is already running")
+	ErrSchedulerStopped = errors.New("scheduler is stopped")
+)
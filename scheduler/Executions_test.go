@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, backoff(1))
+	assert.Equal(t, 20*time.Millisecond, backoff(2))
+	assert.Equal(t, 40*time.Millisecond, backoff(3))
+	assert.Equal(t, 50*time.Millisecond, backoff(4))
+}
+
+func TestHistory(t *testing.T) {
+	h := newHistory(3)
+	for i := 1; i <= 5; i++ {
+		h.add(Execution{Attempts: i})
+	}
+
+	var attempts []int
+	for _, e := range h.list() {
+		attempts = append(attempts, e.Attempts)
+	}
+	assert.Equal(t, []int{3, 4, 5}, attempts)
+}
+
+func TestScheduler_Retry(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	failure := errors.New("failure")
+	var calls int64
+	s := NewScheduler(doneCh, nil)
+	s.Register(
+		NewAction(
+			"job",
+			NewPeriodicSchedule(time.Hour),
+			func(ctx context.Context) error {
+				if atomic.AddInt64(&calls, 1) < 3 {
+					return failure
+				}
+				return nil
+			},
+		),
+		WithRetry(3, ConstantBackoff(time.Millisecond)),
+	)
+
+	require.NoError(t, s.RunNow(ctx, "job"))
+	assert.Equal(t, int64(3), atomic.LoadInt64(&calls))
+
+	history, err := s.History("job")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 3, history[0].Attempts)
+	assert.NoError(t, history[0].Err)
+
+	_, err = s.History("unknown")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestScheduler_Timeout(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	s := NewScheduler(doneCh, nil)
+	s.Register(
+		NewAction(
+			"job",
+			NewPeriodicSchedule(time.Hour),
+			func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		),
+		WithTimeout(10*time.Millisecond),
+		WithRetry(1, nil),
+	)
+
+	require.ErrorIs(t, s.RunNow(ctx, "job"), context.DeadlineExceeded)
+
+	history, err := s.History("job")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 2, history[0].Attempts)
+	assert.GreaterOrEqual(t, history[0].Duration, 20*time.Millisecond)
+}
+
+func TestScheduler_ConcurrencyPolicy(t *testing.T) {
+	type Test struct {
+		policy ConcurrencyPolicy
+		second error
+		first  error
+	}
+
+	tests := map[string]Test{
+		"allow": {
+			policy: ConcurrencyAllow,
+			second: nil,
+			first:  nil,
+		},
+		"forbid": {
+			policy: ConcurrencyForbid,
+			second: ErrJobRunning,
+			first:  nil,
+		},
+		"replace": {
+			policy: ConcurrencyReplace,
+			second: nil,
+			first:  context.Canceled,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			doneCh := make(chan struct{})
+			defer close(doneCh)
+
+			var calls int64
+			started := make(chan struct{})
+			release := make(chan struct{})
+			s := NewScheduler(doneCh, nil)
+			s.Register(
+				NewAction(
+					"job",
+					NewPeriodicSchedule(time.Hour),
+					func(ctx context.Context) error {
+						if atomic.AddInt64(&calls, 1) > 1 {
+							return nil
+						}
+						close(started)
+						select {
+						case <-ctx.Done():
+							return ctx.Err()
+						case <-release:
+							return nil
+						}
+					},
+				),
+				WithConcurrencyPolicy(test.policy),
+			)
+
+			first := make(chan error, 1)
+			go func() {
+				first <- s.RunNow(ctx, "job")
+			}()
+			<-started
+
+			assert.ErrorIs(t, s.RunNow(ctx, "job"), test.second)
+			close(release)
+			err := <-first
+			if test.first == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.first)
+			}
+		})
+	}
+}
+
+func TestScheduler_AsyncAction(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var calls int64
+	release := make(chan struct{})
+	s := NewScheduler(doneCh, nil)
+	s.Register(NewAsyncAction(
+		"job",
+		NewPeriodicSchedule(5*time.Millisecond),
+		func(ctx context.Context) error {
+			atomic.AddInt64(&calls, 1)
+			<-release
+			return nil
+		},
+	))
+	s.Start(ctx)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&calls))
+	assert.True(t, s.Jobs()[0].Running)
+
+	close(release)
+	time.Sleep(30 * time.Millisecond)
+	assert.Greater(t, atomic.LoadInt64(&calls), int64(1))
+
+	history, err := s.History("job")
+	require.NoError(t, err)
+	assert.NotEmpty(t, history)
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/Adverax/core/json"
+	"github.com/Adverax/core/log"
+	"github.com/Adverax/core/pubsub"
+	"time"
+)
+
+type JobEventKind string
+
+const (
+	JobStarted   JobEventKind = "started"
+	JobSucceeded JobEventKind = "succeeded"
+	JobFailed    JobEventKind = "failed"
+	JobSkipped   JobEventKind = "skipped"
+)
+
+// JobEvent is a lifecycle event of the job published by the scheduler.
+// Started, succeeded and failed events are published for each attempt.
+// Skipped event contains the reason in the Err.
+type JobEvent struct {
+	Kind     JobEventKind
+	Job      string
+	RunID    string
+	Attempt  int
+	Duration time.Duration
+	// Retry is true if the failed attempt will be retried.
+	Retry bool
+	Time  time.Time
+	Err   error
+}
+
+func (that JobEvent) MarshalJSON() ([]byte, error) {
+	var msg string
+	if that.Err != nil {
+		msg = that.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Kind     JobEventKind  `json:"kind"`
+		Job      string        `json:"job"`
+		RunID    string        `json:"run_id,omitempty"`
+		Attempt  int           `json:"attempt,omitempty"`
+		Duration time.Duration `json:"duration,omitempty"`
+		Retry    bool          `json:"retry,omitempty"`
+		Time     time.Time     `json:"time"`
+		Error    string        `json:"error,omitempty"`
+	}{
+		Kind:     that.Kind,
+		Job:      that.Job,
+		RunID:    that.RunID,
+		Attempt:  that.Attempt,
+		Duration: that.Duration,
+		Retry:    that.Retry,
+		Time:     that.Time,
+		Error:    msg,
+	})
+}
+
+func (s *Scheduler) emit(ctx context.Context, event JobEvent) {
+	if s.notifier == nil {
+		return
+	}
+
+	event.RunID = PipelineRunID(ctx)
+	event.Time = s.clock.Now()
+	s.notifier.Publish(ctx, event)
+}
+
+func (s *Scheduler) skip(ctx context.Context, event *event, reason error) {
+	s.emit(ctx, JobEvent{Kind: JobSkipped, Job: event.Name(), Err: reason})
+}
+
+// NewJobEventLogger returns subscriber, that logs job events:
+// failures as errors (warnings, if the attempt will be retried), successes as info and others as debug.
+func NewJobEventLogger(logger log.Logger) pubsub.Handler[JobEvent] {
+	return pubsub.HandlerFunc[JobEvent](func(ctx context.Context, ev *pubsub.Event[JobEvent]) {
+		e := ev.Entity()
+		fields := log.Fields{
+			log.FieldKeyEntity:  log.EntityScheduler,
+			log.FieldKeyAction:  string(e.Kind),
+			log.FieldKeySubject: e.Job,
+			FieldKeyRunID:       e.RunID,
+		}
+		if e.Attempt != 0 {
+			fields[FieldKeyAttempt] = e.Attempt
+		}
+		if e.Kind == JobSucceeded || e.Kind == JobFailed {
+			fields[log.FieldKeyDuration] = e.Duration.String()
+		}
+
+		l := logger.WithFields(ctx, fields)
+		if e.Err != nil {
+			l = l.WithError(ctx, e.Err)
+		}
+
+		switch e.Kind {
+		case JobFailed:
+			if e.Retry {
+				l.Warningf(ctx, "job %s failed, retrying", e.Job)
+			} else {
+				l.Errorf(ctx, "job %s failed", e.Job)
+			}
+		case JobSucceeded:
+			l.Infof(ctx, "job %s succeeded", e.Job)
+		default:
+			l.Debugf(ctx, "job %s %s", e.Job, e.Kind)
+		}
+	})
+}
+
+const (
+	FieldKeyRunID   = "run_id"
+	FieldKeyAttempt = "attempt"
+)
+
+var (
+	ErrJobPaused        = errors.New("job is paused")
+	ErrLeaseNotAcquired = errors.New("lease is not acquired")
+)
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Adverax/core/log"
+	"github.com/Adverax/core/log/logrus"
+	"github.com/Adverax/core/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduler_JobEvents(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	notifier, err := pubsub.NewBuilder[JobEvent]().Subject("scheduler").Build()
+	require.NoError(t, err)
+	sub := notifier.SubscribeChannel(ctx, 10).(*pubsub.ChannelSubscription[JobEvent])
+
+	failure := errors.New("failure")
+	calls := 0
+	s := NewScheduler(doneCh, nil, WithNotifier(notifier))
+	require.NoError(t, s.Register(
+		NewAction(
+			"job",
+			NewManualSchedule(),
+			func(ctx context.Context) error {
+				calls++
+				if calls == 1 {
+					return failure
+				}
+				return nil
+			},
+		),
+		WithRetry(1, nil),
+	))
+
+	require.NoError(t, s.RunNow(ctx, "job"))
+
+	var events []string
+	var runID string
+	for i := 0; i < 4; i++ {
+		select {
+		case ev := <-sub.Channel():
+			e := ev.Entity()
+			events = append(events, fmt.Sprintf("%s %d %v %v", e.Kind, e.Attempt, e.Retry, e.Err))
+			if runID == "" {
+				runID = e.RunID
+			}
+			assert.Equal(t, "job", e.Job)
+			assert.Equal(t, runID, e.RunID)
+		case <-time.After(time.Second):
+			t.Fatal("events are not published")
+		}
+	}
+
+	assert.NotEmpty(t, runID)
+	assert.ElementsMatch(t, []string{
+		"started 1 false <nil>",
+		"failed 1 true failure",
+		"started 2 false <nil>",
+		"succeeded 2 false <nil>",
+	}, events)
+}
+
+func TestNewJobEventLogger(t *testing.T) {
+	var mx sync.Mutex
+	buf := &bytes.Buffer{}
+	l, err := log.NewLogrusBuilder().
+		Output(&lockedWriter{mx: &mx, w: buf}).
+		Level(log.DebugLevel).
+		Formatter(&logrus.JSONFormatter{DisableTimestamp: true}).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	notifier, err := pubsub.NewBuilder[JobEvent]().Subject("scheduler").Build()
+	require.NoError(t, err)
+	notifier.SubscribeHandler(ctx, NewJobEventLogger(log.NewLogger(l, nil)))
+
+	err = notifier.Publish(ctx, JobEvent{
+		Kind:     JobFailed,
+		Job:      "job",
+		RunID:    "run",
+		Attempt:  2,
+		Duration: time.Second,
+		Err:      errors.New("failure"),
+	}).Wait()
+	require.NoError(t, err)
+
+	mx.Lock()
+	defer mx.Unlock()
+	out := strings.TrimSpace(buf.String())
+	assert.JSONEq(t, `{
+		"level": "error",
+		"msg": "job job failed",
+		"entity": "SCHEDULER",
+		"action": "failed",
+		"subject": "job",
+		"run_id": "run",
+		"attempt": 2,
+		"duration": "1s",
+		"error": "failure"
+	}`, out)
+}
+
+type lockedWriter struct {
+	mx *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (that *lockedWriter) Write(p []byte) (int, error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	return that.w.Write(p)
+}
@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// JobInfo is a snapshot of the registered event.
+type JobInfo struct {
+	Name         string
+	Schedule     string
+	Dependencies []string
+	NextTick     time.Time
+	LastRun      time.Time
+	LastError    error
+	Running      bool
+	Paused       bool
+}
+
+type scheduled interface {
+	Schedule() Schedule
+}
+
+// Jobs returns snapshot of all registered events ordered by name.
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	res := make([]JobInfo, 0, len(s.jobs))
+	for _, ev := range s.jobs {
+		res = append(res, JobInfo{
+			Name:         ev.Name(),
+			Schedule:     scheduleString(ev.Event),
+			Dependencies: append([]string(nil), ev.dependencies...),
+			NextTick:     ev.nextTick,
+			LastRun:      ev.lastRun,
+			LastError:    ev.lastErr,
+			Running:      ev.running > 0,
+			Paused:       ev.paused,
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res
+}
+
+// Unregister removes event from the scheduler. Running execution is not interrupted.
+func (s *Scheduler) Unregister(name string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	ev, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	s.remove(ev)
+	s.notify()
+	return nil
+}
+
+// Pause suspends the event: its ticks are skipped until Resume is called.
+func (s *Scheduler) Pause(name string) error {
+	return s.setPaused(name, true)
+}
+
+func (s *Scheduler) Resume(name string) error {
+	return s.setPaused(name, false)
+}
+
+// RunNow executes the event immediately regardless of its schedule and pause and waits for the result.
+// Dependents of the event are triggered in the background.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mx.Lock()
+	ev, ok := s.jobs[name]
+	s.mx.Unlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	ctx = withPipelineRunID(ctx)
+	s.startPipeline(ctx, ev)
+	err := s.run(ctx, ev, nil)
+	s.complete(ctx, ev, err)
+	return err
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	ev, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	ev.paused = paused
+	if !paused {
+		// notifications received during the pause are handled now
+		s.wake(ev)
+	}
+	return nil
+}
+
+func (s *Scheduler) remove(ev *event) {
+	ev.removed = true
+	delete(s.jobs, ev.Name())
+	s.dequeue(ev)
+}
+
+// notify wakes up the working routine to reconsider the nearest event.
+func (s *Scheduler) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func scheduleString(e Event) string {
+	if s, ok := e.(scheduled); ok {
+		return s.Schedule().String()
+	}
+
+	return e.String()
+}
+
+// scheduleOf returns schedule of the event. Event without accessor is considered as its own schedule.
+func scheduleOf(e Event) Schedule {
+	if s, ok := e.(scheduled); ok {
+		return s.Schedule()
+	}
+
+	return e
+}
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+)
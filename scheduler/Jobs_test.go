@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Jobs(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	failure := errors.New("failure")
+	var ticks1, ticks2 int64
+	s := NewScheduler(doneCh, nil)
+	s.Register(NewAction(
+		"job1",
+		NewPeriodicSchedule(10*time.Millisecond),
+		func(ctx context.Context) error {
+			atomic.AddInt64(&ticks1, 1)
+			return nil
+		},
+	))
+	s.Register(NewAction(
+		"job2",
+		NewPeriodicSchedule(time.Hour),
+		func(ctx context.Context) error {
+			atomic.AddInt64(&ticks2, 1)
+			return failure
+		},
+	))
+	s.Start(ctx)
+
+	require.NoError(t, s.Pause("job1"))
+	require.ErrorIs(t, s.RunNow(ctx, "job2"), failure)
+	require.ErrorIs(t, s.RunNow(ctx, "job3"), ErrJobNotFound)
+
+	time.Sleep(25 * time.Millisecond)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&ticks1))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&ticks2))
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "job1", jobs[0].Name)
+	assert.Equal(t, "every 10ms", jobs[0].Schedule)
+	assert.True(t, jobs[0].Paused)
+	assert.True(t, jobs[0].LastRun.IsZero())
+	assert.Equal(t, "job2", jobs[1].Name)
+	assert.ErrorIs(t, jobs[1].LastError, failure)
+	assert.False(t, jobs[1].LastRun.IsZero())
+	assert.True(t, jobs[1].NextTick.After(time.Now()))
+	assert.False(t, jobs[1].Running)
+
+	require.NoError(t, s.Resume("job1"))
+	time.Sleep(25 * time.Millisecond)
+	assert.Greater(t, atomic.LoadInt64(&ticks1), int64(0))
+
+	require.NoError(t, s.Unregister("job1"))
+	require.ErrorIs(t, s.Unregister("job1"), ErrJobNotFound)
+	count := atomic.LoadInt64(&ticks1)
+	time.Sleep(25 * time.Millisecond)
+	assert.Equal(t, count, atomic.LoadInt64(&ticks1))
+	assert.Len(t, s.Jobs(), 1)
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_StartContext(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var ticks int64
+	s := NewScheduler(nil, nil, WithClock(clock))
+	require.NoError(t, s.Register(NewAction(
+		"job",
+		NewPeriodicSchedule(10*time.Millisecond),
+		func(ctx context.Context) error {
+			atomic.AddInt64(&ticks, 1)
+			return nil
+		},
+	)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Millisecond)
+	clock.BlockUntil(1)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&ticks))
+
+	cancel()
+	require.NoError(t, s.Stop(context.Background()))
+	clock.Advance(time.Second)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&ticks))
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := NewScheduler(nil, nil)
+	require.NoError(t, s.Register(NewAsyncAction(
+		"job",
+		NewPeriodicSchedule(time.Millisecond),
+		func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	)))
+	s.Start(context.Background())
+	<-started
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- s.Stop(context.Background())
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("stop doesn't wait for running job")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-stopped)
+	assert.ErrorIs(t, s.RunNow(context.Background(), "job"), ErrSchedulerStopped)
+}
+
+func TestScheduler_StopDeadline(t *testing.T) {
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	s := NewScheduler(nil, nil)
+	require.NoError(t, s.Register(NewAction(
+		"job",
+		NewManualSchedule(),
+		func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			close(canceled)
+			return ctx.Err()
+		},
+	)))
+	s.Start(context.Background())
+
+	go func() {
+		_ = s.RunNow(context.Background(), "job")
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("context of the job is not canceled")
+	}
+}
+
+func TestScheduler_RegisterAfterStart(t *testing.T) {
+	s := NewScheduler(nil, nil)
+	s.Start(context.Background())
+	defer s.Stop(context.Background())
+
+	fired := make(chan struct{}, 1)
+	require.NoError(t, s.Register(NewAction(
+		"job",
+		NewPeriodicSchedule(10*time.Millisecond),
+		func(ctx context.Context) error {
+			select {
+			case fired <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	)))
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("job registered after start is not fired")
+	}
+}
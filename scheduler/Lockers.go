@@ -0,0 +1,233 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Adverax/core"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Locker grants leases, that guarantee that only one replica fires each tick of the event.
+type Locker interface {
+	// Acquire obtains lease on the key for the ttl. It returns false, if the lease is held by another owner.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Renew prolongs lease held by the owner.
+	Renew(ctx context.Context, key string, ttl time.Duration) error
+	// Release frees lease held by the owner.
+	Release(ctx context.Context, key string) error
+}
+
+type lease struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (that *lease) isHeld(owner string, now time.Time) bool {
+	return that.Owner != owner && now.Before(that.ExpiresAt)
+}
+
+type memoryLeases struct {
+	mx     sync.Mutex
+	leases map[string]*lease
+}
+
+// MemoryLocker keeps leases in the memory of the process.
+type MemoryLocker struct {
+	owner  string
+	clock  Clock
+	leases *memoryLeases
+}
+
+// NewMemoryLocker creates locker, that measures leases by the clock.
+// Pass the clock of the scheduler, or nil for the real time.
+func NewMemoryLocker(clock Clock) *MemoryLocker {
+	if clock == nil {
+		clock = RealClock{}
+	}
+
+	return &MemoryLocker{
+		owner: core.NewGUID(),
+		clock: clock,
+		leases: &memoryLeases{
+			leases: make(map[string]*lease),
+		},
+	}
+}
+
+// Replica returns locker of another owner, that shares the same leases.
+func (that *MemoryLocker) Replica() *MemoryLocker {
+	return &MemoryLocker{
+		owner:  core.NewGUID(),
+		clock:  that.clock,
+		leases: that.leases,
+	}
+}
+
+func (that *MemoryLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	that.leases.mx.Lock()
+	defer that.leases.mx.Unlock()
+
+	now := that.clock.Now()
+	if l, ok := that.leases.leases[key]; ok && l.isHeld(that.owner, now) {
+		return false, nil
+	}
+
+	that.leases.leases[key] = &lease{Owner: that.owner, ExpiresAt: now.Add(ttl)}
+	that.cleanup(now)
+	return true, nil
+}
+
+func (that *MemoryLocker) Renew(ctx context.Context, key string, ttl time.Duration) error {
+	that.leases.mx.Lock()
+	defer that.leases.mx.Unlock()
+
+	l, ok := that.leases.leases[key]
+	if !ok || l.Owner != that.owner {
+		return ErrLeaseNotHeld
+	}
+
+	l.ExpiresAt = that.clock.Now().Add(ttl)
+	return nil
+}
+
+func (that *MemoryLocker) Release(ctx context.Context, key string) error {
+	that.leases.mx.Lock()
+	defer that.leases.mx.Unlock()
+
+	l, ok := that.leases.leases[key]
+	if !ok || l.Owner != that.owner {
+		return ErrLeaseNotHeld
+	}
+
+	delete(that.leases.leases, key)
+	return nil
+}
+
+func (that *MemoryLocker) cleanup(now time.Time) {
+	for key, l := range that.leases.leases {
+		if !now.Before(l.ExpiresAt) {
+			delete(that.leases.leases, key)
+		}
+	}
+}
+
+// FileLocker keeps leases in the single file guarded by the file lock.
+// It is suitable for replicas running on the single host.
+type FileLocker struct {
+	owner    string
+	fileName string
+	clock    Clock
+}
+
+// NewFileLocker creates locker, that measures leases by the clock.
+// Pass the clock of the scheduler, or nil for the real time.
+func NewFileLocker(fileName string, clock Clock) (*FileLocker, error) {
+	if clock == nil {
+		clock = RealClock{}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileLocker{
+		owner:    core.NewGUID(),
+		fileName: fileName,
+		clock:    clock,
+	}, nil
+}
+
+func (that *FileLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	var acquired bool
+	err := that.update(func(leases map[string]*lease, now time.Time) error {
+		if l, ok := leases[key]; ok && l.isHeld(that.owner, now) {
+			return nil
+		}
+
+		acquired = true
+		leases[key] = &lease{Owner: that.owner, ExpiresAt: now.Add(ttl)}
+		return nil
+	})
+
+	return acquired, err
+}
+
+func (that *FileLocker) Renew(ctx context.Context, key string, ttl time.Duration) error {
+	return that.update(func(leases map[string]*lease, now time.Time) error {
+		l, ok := leases[key]
+		if !ok || l.Owner != that.owner {
+			return ErrLeaseNotHeld
+		}
+
+		l.ExpiresAt = now.Add(ttl)
+		return nil
+	})
+}
+
+func (that *FileLocker) Release(ctx context.Context, key string) error {
+	return that.update(func(leases map[string]*lease, now time.Time) error {
+		l, ok := leases[key]
+		if !ok || l.Owner != that.owner {
+			return ErrLeaseNotHeld
+		}
+
+		delete(leases, key)
+		return nil
+	})
+}
+
+// update reads, modifies and writes leases under the exclusive file lock.
+func (that *FileLocker) update(action func(leases map[string]*lease, now time.Time) error) error {
+	f, err := os.OpenFile(that.fileName, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return err
+	}
+	defer unlockFile(f)
+
+	leases := make(map[string]*lease)
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &leases); err != nil {
+			return fmt.Errorf("leases %s: %w", that.fileName, err)
+		}
+	}
+
+	now := that.clock.Now()
+	if err := action(leases, now); err != nil {
+		return err
+	}
+
+	for key, l := range leases {
+		if !now.Before(l.ExpiresAt) {
+			delete(leases, key)
+		}
+	}
+
+	data, err = json.Marshal(leases)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err = f.WriteAt(data, 0)
+	return err
+}
+
+var (
+	ErrLeaseNotHeld = errors.New("lease is not held")
+)
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testLocker(t *testing.T, clock *FakeClock, locker, replica Locker) {
+	ctx := context.Background()
+
+	ok, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = replica.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.ErrorIs(t, replica.Renew(ctx, "job", time.Minute), ErrLeaseNotHeld)
+	require.ErrorIs(t, replica.Release(ctx, "job"), ErrLeaseNotHeld)
+	require.NoError(t, locker.Renew(ctx, "job", time.Minute))
+	require.NoError(t, locker.Release(ctx, "job"))
+
+	ok, err = replica.Acquire(ctx, "job", time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	clock.Advance(time.Millisecond)
+	ok, err = locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestMemoryLocker(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	locker := NewMemoryLocker(clock)
+	testLocker(t, clock, locker, locker.Replica())
+}
+
+func TestFileLocker(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "leases.json")
+	clock := NewFakeClock(time.Now())
+	locker, err := NewFileLocker(fileName, clock)
+	require.NoError(t, err)
+	replica, err := NewFileLocker(fileName, clock)
+	require.NoError(t, err)
+	testLocker(t, clock, locker, replica)
+}
+
+func TestScheduler_Locker(t *testing.T) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var ticks int64
+	locker := NewMemoryLocker(nil)
+	for _, l := range []Locker{locker, locker.Replica()} {
+		s := NewScheduler(doneCh, nil, WithLocker(l, 30*time.Millisecond))
+		s.Register(NewAction(
+			"job",
+			NewPeriodicSchedule(50*time.Millisecond),
+			func(ctx context.Context) error {
+				atomic.AddInt64(&ticks, 1)
+				return nil
+			},
+		))
+		s.Start(context.Background())
+	}
+
+	time.Sleep(120 * time.Millisecond)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&ticks))
+}
@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/Adverax/core/pubsub"
+	"sync"
+	"time"
+)
+
+// Notifier is a schedule, that ticks in response to notifications instead of time.
+type Notifier interface {
+	Schedule
+	Notify()
+}
+
+// notifiable is implemented by the schedules, that are bound to the scheduler.
+type notifiable interface {
+	// bind sets clock of the scheduler and callback, that reschedules the event after notification.
+	bind(clock Clock, wake func())
+	// consume marks notifications up to the tick as handled.
+	consume(tick time.Time)
+}
+
+// reschedule recalculates the tick of the event after notification of its schedule.
+func (s *Scheduler) reschedule(event *event) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if event.removed {
+		return
+	}
+
+	event.setNextTick(event.NextTick(s.clock.Now()))
+	s.enqueue(event)
+	s.notify()
+}
+
+type notifications struct {
+	mx    sync.Mutex
+	clock Clock
+	wake  func()
+}
+
+func (that *notifications) bind(clock Clock, wake func()) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.clock = clock
+	that.wake = wake
+}
+
+// DebounceSchedule ticks once, when the window passed since the last notification.
+// So the burst of notifications is coalesced into the single execution.
+type DebounceSchedule struct {
+	notifications
+	window time.Duration
+	tick   time.Time
+}
+
+func (s *DebounceSchedule) Notify() {
+	s.mx.Lock()
+	s.tick = s.clock.Now().Add(s.window)
+	wake := s.wake
+	s.mx.Unlock()
+
+	if wake != nil {
+		wake()
+	}
+}
+
+func (s *DebounceSchedule) NextTick(now time.Time) time.Time {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.tick
+}
+
+func (s *DebounceSchedule) String() string {
+	return fmt.Sprintf("debounce %s", s.window.String())
+}
+
+func (s *DebounceSchedule) consume(tick time.Time) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if !s.tick.After(tick) {
+		s.tick = time.Time{}
+	}
+}
+
+func NewDebounceSchedule(window time.Duration) *DebounceSchedule {
+	return &DebounceSchedule{
+		notifications: notifications{clock: RealClock{}},
+		window:        window,
+	}
+}
+
+// ThrottleSchedule ticks after notification, but no more than limit times per period.
+// Notifications received while the limit is exhausted are coalesced into the single execution.
+type ThrottleSchedule struct {
+	notifications
+	limit   int
+	period  time.Duration
+	pending bool
+	ticks   []time.Time
+}
+
+func (s *ThrottleSchedule) Notify() {
+	s.mx.Lock()
+	s.pending = true
+	wake := s.wake
+	s.mx.Unlock()
+
+	if wake != nil {
+		wake()
+	}
+}
+
+func (s *ThrottleSchedule) NextTick(now time.Time) time.Time {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if !s.pending {
+		return time.Time{}
+	}
+
+	if len(s.ticks) < s.limit {
+		return now
+	}
+
+	if tick := s.ticks[0].Add(s.period); tick.After(now) {
+		return tick
+	}
+
+	return now
+}
+
+func (s *ThrottleSchedule) String() string {
+	return fmt.Sprintf("throttle %d per %s", s.limit, s.period.String())
+}
+
+func (s *ThrottleSchedule) consume(tick time.Time) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if !s.pending {
+		return
+	}
+
+	s.pending = false
+	s.ticks = append(s.ticks, tick)
+	if len(s.ticks) > s.limit {
+		s.ticks = s.ticks[len(s.ticks)-s.limit:]
+	}
+}
+
+func NewThrottleSchedule(limit int, period time.Duration) *ThrottleSchedule {
+	if limit < 1 {
+		limit = 1
+	}
+
+	return &ThrottleSchedule{
+		notifications: notifications{clock: RealClock{}},
+		limit:         limit,
+		period:        period,
+	}
+}
+
+// NotifyOn subscribes notifier to the pubsub, so each published event notifies the schedule.
+func NotifyOn[T any](ctx context.Context, ps *pubsub.PubSub[T], notifier Notifier) pubsub.Subscriber[T] {
+	return ps.SubscribeHandlerFunc(
+		ctx,
+		func(ctx context.Context, event *pubsub.Event[T]) {
+			notifier.Notify()
+		},
+	)
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/Adverax/core/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newNotifiedScheduler(t *testing.T, schedule Schedule) (*Scheduler, *FakeClock, chan time.Time) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	fired := make(chan time.Time, 10)
+	s := NewScheduler(nil, nil, WithClock(clock))
+	require.NoError(t, s.Register(NewAction(
+		"job",
+		schedule,
+		func(ctx context.Context) error {
+			fired <- clock.Now()
+			return nil
+		},
+	)))
+	s.Start(context.Background())
+	t.Cleanup(func() {
+		_ = s.Stop(context.Background())
+	})
+	clock.BlockUntil(1)
+	return s, clock, fired
+}
+
+func expectFired(t *testing.T, fired chan time.Time, expected time.Time) {
+	select {
+	case actual := <-fired:
+		assert.Equal(t, expected, actual)
+	case <-time.After(time.Second):
+		t.Fatal("job is not fired")
+	}
+}
+
+func expectNotFired(t *testing.T, fired chan time.Time) {
+	select {
+	case <-fired:
+		t.Fatal("job is fired unexpectedly")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestDebounceSchedule(t *testing.T) {
+	schedule := NewDebounceSchedule(10 * time.Millisecond)
+	_, clock, fired := newNotifiedScheduler(t, schedule)
+	start := clock.Now()
+
+	schedule.Notify()
+	clock.Advance(2 * time.Millisecond)
+	schedule.Notify()
+	clock.Advance(2 * time.Millisecond)
+	schedule.Notify()
+
+	clock.Advance(9 * time.Millisecond)
+	expectNotFired(t, fired)
+	clock.Advance(time.Millisecond)
+	expectFired(t, fired, start.Add(14*time.Millisecond))
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	expectNotFired(t, fired)
+}
+
+func TestThrottleSchedule(t *testing.T) {
+	schedule := NewThrottleSchedule(2, 10*time.Millisecond)
+	_, clock, fired := newNotifiedScheduler(t, schedule)
+	start := clock.Now()
+
+	schedule.Notify()
+	expectFired(t, fired, start)
+	clock.Advance(time.Millisecond)
+	schedule.Notify()
+	expectFired(t, fired, start.Add(time.Millisecond))
+
+	schedule.Notify()
+	schedule.Notify()
+	expectNotFired(t, fired)
+	clock.BlockUntil(1)
+	clock.Advance(9 * time.Millisecond)
+	expectFired(t, fired, start.Add(10*time.Millisecond))
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	expectNotFired(t, fired)
+}
+
+func TestThrottleSchedule_Running(t *testing.T) {
+	schedule := NewThrottleSchedule(1, 10*time.Millisecond)
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	fired := make(chan time.Time, 10)
+	release := make(chan struct{})
+	s := NewScheduler(nil, nil, WithClock(clock))
+	require.NoError(t, s.Register(NewAsyncAction(
+		"job",
+		schedule,
+		func(ctx context.Context) error {
+			fired <- clock.Now()
+			<-release
+			return nil
+		},
+	)))
+	s.Start(context.Background())
+	t.Cleanup(func() {
+		_ = s.Stop(context.Background())
+	})
+	t.Cleanup(func() {
+		close(release)
+	})
+	clock.BlockUntil(1)
+	start := clock.Now()
+
+	schedule.Notify()
+	expectFired(t, fired, start)
+	schedule.Notify()
+
+	// the window opens, while the job is still running
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Millisecond)
+	expectNotFired(t, fired)
+
+	release <- struct{}{}
+	expectFired(t, fired, start.Add(10*time.Millisecond))
+}
+
+func TestNotifyOn(t *testing.T) {
+	schedule := NewThrottleSchedule(1, time.Hour)
+	_, clock, fired := newNotifiedScheduler(t, schedule)
+
+	ps, err := pubsub.NewBuilder[string]().Subject("changes").Build()
+	require.NoError(t, err)
+	NotifyOn[string](context.Background(), ps, schedule)
+
+	require.NoError(t, ps.Publish(context.Background(), "changed").Wait())
+	expectFired(t, fired, clock.Now())
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"github.com/Adverax/core/pubsub"
+	"time"
+)
+
+type Option func(*Scheduler)
+
+// WithLocker makes scheduler to acquire the lease on the event before triggering it,
+// so only one replica fires each tick. Leases are not released after the execution:
+// they expire after the ttl and prevent late replicas from firing the same tick again.
+// So the ttl must exceed the clock skew of the replicas and be less than the interval between ticks.
+// Create the locker with the same clock, that is passed to WithClock.
+func WithLocker(locker Locker, ttl time.Duration) Option {
+	return func(s *Scheduler) {
+		s.locker = locker
+		s.leaseTTL = ttl
+	}
+}
+
+// WithJobStore makes scheduler to persist last and next runs of the events.
+func WithJobStore(store JobStore) Option {
+	return func(s *Scheduler) {
+		s.store = store
+	}
+}
+
+// WithClock replaces the real time with the clock, e.g. FakeClock in tests.
+func WithClock(clock Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithNotifier makes scheduler to publish lifecycle events of the jobs.
+func WithNotifier(notifier *pubsub.PubSub[JobEvent]) Option {
+	return func(s *Scheduler) {
+		s.notifier = notifier
+	}
+}
+
+// MisfirePolicy defines what to do with runs missed while the scheduler was stopped.
+type MisfirePolicy int
+
+const (
+	// MisfireSkip skips missed runs.
+	MisfireSkip MisfirePolicy = iota
+	// MisfireRunOnce runs event once for all missed runs.
+	MisfireRunOnce
+	// MisfireRunAll runs event for each missed run.
+	MisfireRunAll
+)
+
+type JobOption func(*event)
+
+func WithMisfirePolicy(policy MisfirePolicy) JobOption {
+	return func(e *event) {
+		e.misfire = policy
+	}
+}
+
+// WithTimeout limits duration of each attempt of the event by the context deadline.
+func WithTimeout(timeout time.Duration) JobOption {
+	return func(e *event) {
+		e.timeout = timeout
+	}
+}
+
+// WithRetry repeats failed execution of the event up to the retries times.
+// Backoff defines delay before each retry, nil means no delay.
+func WithRetry(retries int, backoff Backoff) JobOption {
+	return func(e *event) {
+		e.retries = retries
+		e.backoff = backoff
+	}
+}
+
+// ConcurrencyPolicy defines what to do, when the event is triggered while its previous execution is running.
+type ConcurrencyPolicy int
+
+const (
+	// ConcurrencyAllow runs executions concurrently.
+	ConcurrencyAllow ConcurrencyPolicy = iota
+	// ConcurrencyForbid skips the new execution.
+	ConcurrencyForbid
+	// ConcurrencyReplace cancels context of the running execution and starts the new one.
+	ConcurrencyReplace
+)
+
+// WithConcurrencyPolicy overrides default policy: ConcurrencyForbid for the Asynchronous events
+// and ConcurrencyAllow for others.
+func WithConcurrencyPolicy(policy ConcurrencyPolicy) JobOption {
+	return func(e *event) {
+		e.concurrency = policy
+	}
+}
+
+// WithHistory sets number of the recent executions kept in the memory.
+func WithHistory(size int) JobOption {
+	return func(e *event) {
+		e.history = newHistory(size)
+	}
+}
+
+// WithDependencies makes event to be executed after completion of the named events
+// in the same pipeline run. Event depending on several events waits for all of them.
+func WithDependencies(names ...string) JobOption {
+	return func(e *event) {
+		e.dependencies = append(e.dependencies, names...)
+	}
+}
+
+// WithTriggerRule defines, how results of the dependencies affect execution of the event.
+func WithTriggerRule(rule TriggerRule) JobOption {
+	return func(e *event) {
+		e.trigger = rule
+	}
+}
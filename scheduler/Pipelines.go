@@ -0,0 +1,272 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/Adverax/core"
+	"sort"
+)
+
+type contextKeyType int
+
+const contextKeyRunID contextKeyType = 0
+
+// PipelineRunID returns identifier of the pipeline run, that executes the event.
+// Event and all its dependents, triggered by the same execution, share the same identifier.
+func PipelineRunID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKeyRunID).(string)
+	return id
+}
+
+func withPipelineRunID(ctx context.Context) context.Context {
+	if PipelineRunID(ctx) != "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKeyRunID, core.NewGUID())
+}
+
+// TriggerRule defines, when the dependent event is executed after its dependencies are completed.
+type TriggerRule int
+
+const (
+	// TriggerAllSuccess executes event, if all dependencies succeeded. Otherwise, event is skipped.
+	TriggerAllSuccess TriggerRule = iota
+	// TriggerAllDone executes event regardless of the results of dependencies.
+	TriggerAllDone
+	// TriggerOneFailed executes event, if any dependency failed. Otherwise, event is skipped.
+	TriggerOneFailed
+)
+
+type outcome int
+
+const (
+	outcomeSucceeded outcome = iota
+	outcomeFailed
+	outcomeSkipped
+)
+
+func outcomeOf(err error) outcome {
+	switch {
+	case err == nil:
+		return outcomeSucceeded
+	case errors.Is(err, ErrJobRunning), errors.Is(err, ErrSchedulerStopped):
+		return outcomeSkipped
+	default:
+		return outcomeFailed
+	}
+}
+
+// pipeline is a state of the pipeline run.
+type pipeline struct {
+	members  map[string]bool
+	outcomes map[string]outcome
+	pending  int
+}
+
+// startPipeline starts the pipeline run of the event, if the event has dependents.
+// Members of the run are captured at its start, so the events registered during the run don't participate in it.
+func (s *Scheduler) startPipeline(ctx context.Context, ev *event) {
+	runID := PipelineRunID(ctx)
+	if runID == "" {
+		return
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if _, ok := s.pipelines[runID]; ok {
+		return
+	}
+
+	members := s.downstream(ev.Name())
+	if len(members) == 0 {
+		return
+	}
+
+	members[ev.Name()] = true
+	s.pipelines[runID] = &pipeline{
+		members:  members,
+		outcomes: make(map[string]outcome),
+		pending:  len(members),
+	}
+}
+
+// propagate records outcome of the event in the pipeline run and triggers its dependents,
+// whose dependencies are completed.
+func (s *Scheduler) propagate(ctx context.Context, ev *event, result outcome) {
+	runID := PipelineRunID(ctx)
+	if runID == "" {
+		return
+	}
+
+	s.mx.Lock()
+	p, ok := s.pipelines[runID]
+	if !ok || !p.members[ev.Name()] {
+		s.mx.Unlock()
+		return
+	}
+	if _, done := p.outcomes[ev.Name()]; done {
+		s.mx.Unlock()
+		return
+	}
+	p.pending--
+	p.outcomes[ev.Name()] = result
+
+	var ready []*event
+	for _, d := range s.dependents(ev.Name()) {
+		if _, done := p.outcomes[d.Name()]; p.members[d.Name()] && !done && s.isReady(p, d) {
+			ready = append(ready, d)
+		}
+	}
+
+	if p.pending == 0 {
+		delete(s.pipelines, runID)
+	}
+	s.mx.Unlock()
+
+	for _, d := range ready {
+		s.trigger(ctx, d)
+	}
+}
+
+// trigger executes the dependent event according to its trigger rule.
+func (s *Scheduler) trigger(ctx context.Context, event *event) {
+	var reason error
+	s.mx.Lock()
+	switch {
+	case event.removed:
+		reason = ErrJobNotFound
+	case event.paused:
+		reason = ErrJobPaused
+	case !s.isTriggered(s.pipelines[PipelineRunID(ctx)], event):
+		reason = ErrDependencyFailed
+	}
+	s.mx.Unlock()
+
+	if reason != nil {
+		if reason != ErrJobNotFound {
+			s.skip(ctx, event, reason)
+		}
+		s.propagate(ctx, event, outcomeSkipped)
+		return
+	}
+
+	go func() {
+		err := s.run(ctx, event, nil)
+		s.report(ctx, err)
+		s.complete(ctx, event, err)
+	}()
+}
+
+// isReady returns true if all dependencies of the event participating in the pipeline run are completed.
+func (s *Scheduler) isReady(p *pipeline, event *event) bool {
+	for _, name := range event.dependencies {
+		if _, done := p.outcomes[name]; p.members[name] && !done {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Scheduler) isTriggered(p *pipeline, event *event) bool {
+	if event.trigger == TriggerAllDone {
+		return true
+	}
+
+	failed := false
+	for _, name := range event.dependencies {
+		if s.dependencyOutcome(p, name) != outcomeSucceeded {
+			failed = true
+			break
+		}
+	}
+
+	if event.trigger == TriggerOneFailed {
+		return failed
+	}
+
+	return !failed
+}
+
+// dependencyOutcome returns outcome of the dependency in the pipeline run.
+// Dependencies, that don't participate in the run, are evaluated by their last execution.
+func (s *Scheduler) dependencyOutcome(p *pipeline, name string) outcome {
+	if p != nil && p.members[name] {
+		return p.outcomes[name]
+	}
+
+	ev, ok := s.jobs[name]
+	if !ok || ev.lastRun.IsZero() {
+		return outcomeSkipped
+	}
+
+	return outcomeOf(ev.lastErr)
+}
+
+// dependents returns events, that directly depend on the named event, ordered by name.
+func (s *Scheduler) dependents(name string) []*event {
+	var res []*event
+	for _, ev := range s.jobs {
+		for _, dep := range ev.dependencies {
+			if dep == name {
+				res = append(res, ev)
+				break
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+
+	return res
+}
+
+// downstream returns names of all events, that transitively depend on the named event.
+func (s *Scheduler) downstream(name string) map[string]bool {
+	res := make(map[string]bool)
+	queue := []string{name}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, d := range s.dependents(current) {
+			if !res[d.Name()] {
+				res[d.Name()] = true
+				queue = append(queue, d.Name())
+			}
+		}
+	}
+
+	return res
+}
+
+// hasCycle returns true if dependencies of the event lead back to it.
+func (s *Scheduler) hasCycle(event *event) bool {
+	visited := make(map[string]bool)
+	var visit func(deps []string) bool
+	visit = func(deps []string) bool {
+		for _, dep := range deps {
+			if dep == event.Name() {
+				return true
+			}
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+
+			if ev, ok := s.jobs[dep]; ok && visit(ev.dependencies) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return visit(event.dependencies)
+}
+
+var (
+	ErrDependencyCycle  = errors.New("dependency cycle")
+	ErrDependencyFailed = errors.New("dependency failed")
+)
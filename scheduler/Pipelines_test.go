@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Pipeline(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	failure := errors.New("failure")
+	var mx sync.Mutex
+	runs := make(map[string]string)
+	finished := make(chan string, 10)
+	action := func(name string, err error) *Action {
+		return NewAction(
+			name,
+			NewManualSchedule(),
+			func(ctx context.Context) error {
+				mx.Lock()
+				runs[name] = PipelineRunID(ctx)
+				mx.Unlock()
+				finished <- name
+				return err
+			},
+		)
+	}
+
+	s := NewScheduler(doneCh, nil)
+	require.NoError(t, s.Register(action("extract", nil)))
+	require.NoError(t, s.Register(action("load", nil), WithDependencies("transform1", "transform2")))
+	require.NoError(t, s.Register(action("transform1", nil), WithDependencies("extract")))
+	require.NoError(t, s.Register(action("transform2", failure), WithDependencies("extract")))
+	require.NoError(t, s.Register(action("report", nil), WithDependencies("load"), WithTriggerRule(TriggerAllDone)))
+	require.NoError(t, s.Register(action("alert", nil), WithDependencies("transform1", "transform2"), WithTriggerRule(TriggerOneFailed)))
+
+	require.NoError(t, s.RunNow(ctx, "extract"))
+
+	var names []string
+	for i := 0; i < 5; i++ {
+		select {
+		case name := <-finished:
+			names = append(names, name)
+		case <-time.After(time.Second):
+			t.Fatal("pipeline is not completed")
+		}
+	}
+	assert.ElementsMatch(t, []string{"extract", "transform1", "transform2", "alert", "report"}, names)
+
+	mx.Lock()
+	defer mx.Unlock()
+	assert.NotContains(t, runs, "load")
+	require.NotEmpty(t, runs["extract"])
+	for name, id := range runs {
+		assert.Equal(t, runs["extract"], id, name)
+	}
+}
+
+func TestScheduler_PipelineRegisterDuringRun(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan string, 10)
+	action := func(name string) *Action {
+		return NewAction(
+			name,
+			NewManualSchedule(),
+			func(ctx context.Context) error {
+				finished <- name
+				return nil
+			},
+		)
+	}
+
+	s := NewScheduler(doneCh, nil)
+	require.NoError(t, s.Register(NewAction(
+		"extract",
+		NewManualSchedule(),
+		func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	)))
+	require.NoError(t, s.Register(action("transform"), WithDependencies("extract")))
+
+	go func() {
+		_ = s.RunNow(ctx, "extract")
+	}()
+	<-started
+	require.NoError(t, s.Register(action("late"), WithDependencies("extract")))
+	close(release)
+
+	select {
+	case name := <-finished:
+		assert.Equal(t, "transform", name)
+	case <-time.After(time.Second):
+		t.Fatal("pipeline is not completed")
+	}
+
+	assert.Eventually(t, func() bool {
+		s.mx.Lock()
+		defer s.mx.Unlock()
+		return len(s.pipelines) == 0
+	}, time.Second, time.Millisecond)
+	select {
+	case name := <-finished:
+		t.Fatalf("%s is executed by the run started before its registration", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestScheduler_DependencyCycle(t *testing.T) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	handler := func(ctx context.Context) error { return nil }
+	s := NewScheduler(doneCh, nil)
+	require.NoError(t, s.Register(NewAction("a", NewManualSchedule(), handler), WithDependencies("c")))
+	require.NoError(t, s.Register(NewAction("b", NewManualSchedule(), handler), WithDependencies("a")))
+	err := s.Register(NewAction("c", NewManualSchedule(), handler), WithDependencies("b"))
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	assert.Len(t, s.Jobs(), 2)
+}
@@ -2,7 +2,9 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/Adverax/core/pubsub"
 	"sort"
 	"sync"
 	"time"
@@ -15,30 +17,254 @@ type Logger interface {
 type Scheduler struct {
 	mx        sync.Mutex
 	logger    Logger
+	clock     Clock
+	locker    Locker
+	leaseTTL  time.Duration
+	store     JobStore
+	notifier  *pubsub.PubSub[JobEvent]
+	jobs      map[string]*event
 	events    events
-	wakeup    chan struct{}
-	appDoneCh chan struct{}
+	started   bool
+	stopped   bool
+	pipelines map[string]*pipeline
+	// cancels contains cancel functions of the running executions
+	cancels    map[int]context.CancelFunc
+	seq        int
+	executions sync.WaitGroup
+	wakeup     chan struct{}
+	stopCh     chan struct{}
+	doneCh     chan struct{}
+	appDoneCh  chan struct{}
 }
 
-func (s *Scheduler) Register(e Event) {
-	s.update(&event{Event: e}, true)
+// Register adds event to the scheduler. Event with the same name is replaced.
+func (s *Scheduler) Register(e Event, options ...JobOption) error {
+	ev := &event{Event: e, history: newHistory(defaultHistorySize)}
+	if _, ok := e.(Asynchronous); ok {
+		ev.concurrency = ConcurrencyForbid
+	}
+	for _, option := range options {
+		option(ev)
+	}
+
+	// state is loaded before the lock, so the slow store doesn't block the scheduler
+	ctx := context.Background()
+	state, loaded := s.loadState(ctx, ev)
+	initial, err := s.register(ev, state, loaded)
+	if err != nil {
+		return err
+	}
+	if initial != nil {
+		s.saveState(ctx, initial)
+	}
+
+	return nil
 }
 
+// register adds event to the scheduler and applies the loaded state, if the scheduler is started.
+// It returns initial state of the event, that must be saved.
+func (s *Scheduler) register(ev *event, state *JobState, loaded bool) (*JobState, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.hasCycle(ev) {
+		return nil, fmt.Errorf("register %s: %w", ev.Name(), ErrDependencyCycle)
+	}
+
+	if old, ok := s.jobs[ev.Name()]; ok {
+		s.remove(old)
+	}
+	s.jobs[ev.Name()] = ev
+
+	s.recalc(ev)
+	var initial *JobState
+	if s.started && loaded {
+		initial = s.restore(ev, state)
+	}
+	s.enqueue(ev)
+	if n, ok := scheduleOf(ev.Event).(notifiable); ok {
+		n.bind(s.clock, func() { s.reschedule(ev) })
+	}
+	if s.started {
+		s.notify()
+	}
+
+	return initial, nil
+}
+
+// Start runs the scheduler until the context is canceled, appDoneCh is closed or Stop is called.
+// Contexts of the executions are derived from the ctx.
 func (s *Scheduler) Start(ctx context.Context) {
-	go s.work(ctx)
+	s.mx.Lock()
+	if s.started || s.stopped {
+		s.mx.Unlock()
+		return
+	}
+	s.started = true
+	evs := append(events(nil), s.events...)
+	s.mx.Unlock()
+
+	// states are loaded without the lock, so the slow store doesn't block the scheduler
+	states := make(map[*event]*JobState, len(evs))
+	for _, ev := range evs {
+		if state, loaded := s.loadState(ctx, ev); loaded {
+			states[ev] = state
+		}
+	}
+
+	var initials []*JobState
+	s.mx.Lock()
+	for _, ev := range evs {
+		state, loaded := states[ev]
+		if !loaded || ev.removed {
+			continue
+		}
+		if initial := s.restore(ev, state); initial != nil {
+			initials = append(initials, initial)
+		}
+	}
+	s.events = s.pending(s.events)
+	s.mx.Unlock()
+
+	for _, initial := range initials {
+		s.saveState(ctx, initial)
+	}
+
+	go func() {
+		defer close(s.doneCh)
+		s.work(ctx)
+	}()
+}
+
+// Stop stops firing of the events and waits for the running executions.
+// When the ctx is done, contexts of the executions are canceled and Stop returns without waiting for them.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mx.Lock()
+	if s.stopped {
+		s.mx.Unlock()
+		return nil
+	}
+	s.stopped = true
+	started := s.started
+	s.mx.Unlock()
+
+	close(s.stopCh)
+
+	done := make(chan struct{})
+	go func() {
+		if started {
+			<-s.doneCh
+		}
+		s.executions.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mx.Lock()
+		for _, cancel := range s.cancels {
+			cancel()
+		}
+		s.mx.Unlock()
+		return ctx.Err()
+	}
+}
+
+// loadState loads persistent state of the event. It returns false, if there is no store or the state can't be loaded.
+func (s *Scheduler) loadState(ctx context.Context, event *event) (*JobState, bool) {
+	if s.store == nil {
+		return nil, false
+	}
+
+	state, err := s.store.Load(ctx, event.Name())
+	if err != nil {
+		s.logger.LogError(ctx, fmt.Errorf("load state of %s: %w", event.Name(), err))
+		return nil, false
+	}
+	return state, true
+}
+
+func (s *Scheduler) saveState(ctx context.Context, state *JobState) {
+	if err := s.store.Save(ctx, state); err != nil {
+		s.logger.LogError(ctx, fmt.Errorf("save state of %s: %w", state.Name, err))
+	}
+}
+
+// restore applies the loaded state and misfire policy to the event.
+// If the event has no state yet, it returns initial state, that must be saved.
+func (s *Scheduler) restore(event *event, state *JobState) *JobState {
+	if state == nil {
+		// keep the first tick, so frequent restarts don't postpone it
+		return &JobState{Name: event.Name(), NextRun: event.nextTick}
+	}
+
+	event.lastRun = state.LastRun
+	if state.NextRun.IsZero() {
+		if !state.LastRun.IsZero() {
+			// event is already completed
+			event.setNextTick(time.Time{})
+		}
+		return nil
+	}
+
+	if !state.NextRun.Before(s.clock.Now()) {
+		event.setNextTick(state.NextRun)
+		return nil
+	}
+
+	switch event.misfire {
+	case MisfireRunOnce:
+		event.setNextTick(state.NextRun)
+	case MisfireRunAll:
+		event.setNextTick(state.NextRun)
+		event.catchUp = true
+	}
+	return nil
+}
+
+// pending returns sorted list of events, that have next tick.
+func (s *Scheduler) pending(es events) events {
+	res := make(events, 0, len(es))
+	for _, ev := range es {
+		if !ev.nextTick.IsZero() {
+			res = append(res, ev)
+		}
+	}
+	sort.Stable(res)
+	return res
 }
 
 func (s *Scheduler) work(ctx context.Context) {
+	timer := s.clock.NewTimer(time.Minute)
+	defer timer.Stop()
+
 	for {
 		event := s.capture()
+		if !timer.Stop() {
+			// drain tick, that is not received because of wakeup
+			select {
+			case <-timer.C():
+			default:
+			}
+		}
+		timer.Reset(getSleepTime(event, s.clock.Now()))
 
 		select {
+		case <-ctx.Done():
+			s.update(event, false)
+			return
+		case <-s.stopCh:
+			s.update(event, false)
+			return
 		case <-s.appDoneCh:
+			s.update(event, false)
 			return
 		case <-s.wakeup:
 			s.update(event, false)
 			continue
-		case <-time.After(getSleepTime(event)):
+		case <-timer.C():
 			if event == nil {
 				continue
 			}
@@ -49,21 +275,134 @@ func (s *Scheduler) work(ctx context.Context) {
 }
 
 func (s *Scheduler) handle(ctx context.Context, event *event) {
-	err := event.Trigger(ctx)
-	if err != nil {
-		s.logger.LogError(ctx, err)
+	s.mx.Lock()
+	paused, removed, tick := event.paused, event.removed, event.nextTick
+	s.mx.Unlock()
+	if removed {
+		return
+	}
+
+	// notifications are consumed, when the execution is started,
+	// so the skipped ticks don't lose them
+	started := func() {}
+	if n, ok := scheduleOf(event.Event).(notifiable); ok {
+		started = func() { n.consume(tick) }
+	}
+
+	ctx = withPipelineRunID(ctx)
+	if paused {
+		s.update(event, true)
+		s.skip(ctx, event, ErrJobPaused)
+		return
+	}
+
+	unlock := func() {}
+	if s.locker != nil {
+		var ok bool
+		unlock, ok = s.lock(ctx, event)
+		if !ok {
+			s.postpone(event, s.leaseTTL)
+			s.skip(ctx, event, ErrLeaseNotAcquired)
+			return
+		}
 	}
 
+	s.startPipeline(ctx, event)
+	if _, ok := event.Event.(Asynchronous); ok {
+		go func() {
+			// event is rescheduled after the start of the execution,
+			// so the unconsumed notifications don't fire it again
+			updated := false
+			reschedule := func() {
+				s.update(event, true)
+				s.notify()
+				updated = true
+			}
+			err := s.run(ctx, event, func() {
+				started()
+				reschedule()
+			})
+			if !updated {
+				reschedule()
+			}
+			unlock()
+			s.report(ctx, err)
+			s.complete(ctx, event, err)
+		}()
+		return
+	}
+
+	err := s.run(ctx, event, started)
+	unlock()
 	s.update(event, true)
+	s.report(ctx, err)
+	s.complete(ctx, event, err)
 }
 
-func (s *Scheduler) display() {
-	fmt.Println(fmt.Sprintf("Scheduler events: %s", time.Now().String()))
-	for _, event := range s.events {
-		fmt.Println(event.String())
+// report logs error of the execution.
+func (s *Scheduler) report(ctx context.Context, err error) {
+	if err != nil && !errors.Is(err, ErrJobRunning) && !errors.Is(err, ErrSchedulerStopped) {
+		s.logger.LogError(ctx, err)
 	}
 }
 
+// complete persists state of the executed event and triggers its dependents.
+func (s *Scheduler) complete(ctx context.Context, event *event, err error) {
+	if !errors.Is(err, ErrJobRunning) && !errors.Is(err, ErrSchedulerStopped) {
+		s.persist(ctx, event)
+	}
+	s.propagate(ctx, event, outcomeOf(err))
+}
+
+func (s *Scheduler) persist(ctx context.Context, event *event) {
+	if s.store == nil {
+		return
+	}
+
+	s.mx.Lock()
+	state := &JobState{
+		Name:    event.Name(),
+		LastRun: event.lastRun,
+		NextRun: event.nextTick,
+	}
+	s.mx.Unlock()
+
+	s.saveState(ctx, state)
+}
+
+// lock acquires the lease on the event and renews it until unlock is called.
+func (s *Scheduler) lock(ctx context.Context, event *event) (unlock func(), ok bool) {
+	key := event.Name()
+	ok, err := s.locker.Acquire(ctx, key, s.leaseTTL)
+	if err != nil {
+		s.logger.LogError(ctx, fmt.Errorf("acquire lease %s: %w", key, err))
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	done := make(chan struct{})
+	go func() {
+		timer := s.clock.NewTimer(s.leaseTTL / 2)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-timer.C():
+				timer.Reset(s.leaseTTL / 2)
+				if err := s.locker.Renew(ctx, key, s.leaseTTL); err != nil {
+					s.logger.LogError(ctx, fmt.Errorf("renew lease %s: %w", key, err))
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }, true
+}
+
 func (s *Scheduler) capture() *event {
 	s.mx.Lock()
 	defer s.mx.Unlock()
@@ -90,39 +429,138 @@ func (s *Scheduler) update(event *event, recalc bool) {
 }
 
 func (s *Scheduler) refresh(event *event, recalc bool) {
+	if event.removed {
+		return
+	}
 	if recalc {
 		s.recalc(event)
+		if s.waits(event) {
+			// pending notifications are handled, when the event is resumed or its execution is completed (see wake)
+			event.setNextTick(time.Time{})
+		}
 	}
+	s.enqueue(event)
+}
+
+// waits returns true, if the event with the notification schedule can't be started now.
+func (s *Scheduler) waits(event *event) bool {
+	if _, ok := scheduleOf(event.Event).(notifiable); !ok {
+		return false
+	}
+
+	return event.paused || event.running > 0 && event.concurrency == ConcurrencyForbid
+}
+
+// wake reschedules the event with the notification schedule, that may have pending notifications.
+func (s *Scheduler) wake(event *event) {
+	if _, ok := scheduleOf(event.Event).(notifiable); !ok || event.removed || event.paused {
+		return
+	}
+
+	event.setNextTick(event.NextTick(s.clock.Now()))
+	s.enqueue(event)
+	if s.started {
+		s.notify()
+	}
+}
+
+// postpone retries the event with the notification schedule after the delay, so its pending notifications are kept.
+// Other events are recalculated.
+func (s *Scheduler) postpone(event *event, delay time.Duration) {
+	if _, ok := scheduleOf(event.Event).(notifiable); !ok {
+		s.update(event, true)
+		return
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if event.removed {
+		return
+	}
+	event.setNextTick(s.clock.Now().Add(delay))
+	s.enqueue(event)
+}
+
+// enqueue puts event into the queue according to its next tick.
+// Event, that is already queued, is moved.
+func (s *Scheduler) enqueue(event *event) {
+	s.dequeue(event)
 	if event.nextTick.IsZero() {
 		return
 	}
 	s.events = insertIntoEvents(s.events, event)
 }
 
+func (s *Scheduler) dequeue(event *event) {
+	for i, e := range s.events {
+		if e == event {
+			s.events = append(s.events[:i], s.events[i+1:]...)
+			return
+		}
+	}
+}
+
+// recalc calculates next tick of the event starting from the previous tick,
+// so the duration of the execution doesn't shift the schedule.
+// Replicas with the same schedule stay on the same ticks, as the leases of the Locker require.
 func (s *Scheduler) recalc(event *event) {
-	event.nextTick = event.NextTick(time.Now())
-	event.nextTimestamp = event.nextTick.UnixNano()
+	now := s.clock.Now()
+	if event.nextTick.IsZero() {
+		event.setNextTick(event.NextTick(now))
+		return
+	}
+
+	next := event.NextTick(event.nextTick)
+	if !next.IsZero() && next.Before(now) {
+		if !event.catchUp {
+			next = event.NextTick(now)
+		}
+	} else {
+		event.catchUp = false
+	}
+	event.setNextTick(next)
 }
 
 func NewScheduler(
 	appDoneCh chan struct{},
 	logger Logger,
+	options ...Option,
 ) *Scheduler {
-	return &Scheduler{
+	if logger == nil {
+		logger = dummyLogger{}
+	}
+
+	s := &Scheduler{
 		wakeup:    make(chan struct{}, 100),
+		jobs:      make(map[string]*event),
+		pipelines: make(map[string]*pipeline),
+		cancels:   make(map[int]context.CancelFunc),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
 		appDoneCh: appDoneCh,
 		logger:    logger,
+		clock:     RealClock{},
 	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
 }
 
-func getSleepTime(event *event) time.Duration {
+type dummyLogger struct{}
+
+func (dummyLogger) LogError(ctx context.Context, err error) {}
+
+func getSleepTime(event *event, now time.Time) time.Duration {
 	if event == nil {
 		return time.Minute
 	}
 
-	now := time.Now()
 	if event.nextTimestamp < now.UnixNano() {
-		return time.Millisecond
+		return 0
 	}
 
 	return event.nextTick.Sub(now)
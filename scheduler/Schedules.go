@@ -3,6 +3,9 @@ package scheduler
 import (
 	"fmt"
 	"github.com/robfig/cron"
+	"math/rand"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,6 +28,21 @@ func (s *OnceSchedule) String() string {
 	return fmt.Sprintf("once %s", s.tick.String())
 }
 
+// ManualSchedule never fires. It is used for the events executed by RunNow or as dependents only.
+type ManualSchedule struct{}
+
+func (s *ManualSchedule) NextTick(now time.Time) time.Time {
+	return time.Time{}
+}
+
+func (s *ManualSchedule) String() string {
+	return "manual"
+}
+
+func NewManualSchedule() Schedule {
+	return &ManualSchedule{}
+}
+
 type PeriodicSchedule struct {
 	period time.Duration
 }
@@ -43,26 +61,231 @@ func NewPeriodicSchedule(period time.Duration) Schedule {
 	}
 }
 
+// CronSchedule fires at the times matched by the cron spec in its location.
+// Spec may contain optional seconds field and CRON_TZ= (or TZ=) prefix, that overrides location.
+// When the spec has fixed hours, daylight saving time transitions are handled like in the classic cron:
+// ticks skipped by the transition are fired at the moment of transition and repeated
+// wall clock times are fired only once.
 type CronSchedule struct {
-	cron cron.Schedule
-	spec string
+	cron     cron.Schedule
+	spec     string
+	location *time.Location
 }
 
 func (s *CronSchedule) NextTick(now time.Time) time.Time {
-	return s.cron.Next(now)
+	now = now.In(s.location)
+	next := s.cron.Next(now)
+	if next.IsZero() || !s.hasFixedHours() {
+		return next
+	}
+
+	if tick, ok := s.skippedTick(now, next); ok {
+		return tick
+	}
+
+	for isRepeatedWallClock(next) {
+		next = s.cron.Next(next)
+	}
+
+	return next
 }
 
 func (s *CronSchedule) String() string {
-	return fmt.Sprintf("cron %q", s.cron)
+	if s.location == time.Local {
+		return fmt.Sprintf("cron %q", s.spec)
+	}
+
+	return fmt.Sprintf("cron %q in %s", s.spec, s.location)
+}
+
+func (s *CronSchedule) Location() *time.Location {
+	return s.location
+}
+
+func (s *CronSchedule) hasFixedHours() bool {
+	spec, ok := s.cron.(*cron.SpecSchedule)
+	return ok && spec.Hour&cronStarBit == 0
 }
 
-func NewCronSchedule(spec string) (Schedule, error) {
-	s, err := cron.ParseStandard(spec)
+// skippedTick returns moment of the forward transition between now and next,
+// if the schedule matches any wall clock time swallowed by the transition.
+func (s *CronSchedule) skippedTick(now, next time.Time) (time.Time, bool) {
+	_, before := now.Zone()
+	_, after := next.Zone()
+	if after <= before {
+		return time.Time{}, false
+	}
+
+	// find the moment of transition
+	lo, hi := now, next
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, offset := mid.Zone(); offset == before {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	transition := hi.Truncate(time.Second)
+
+	gapStart := wallClock(transition.Add(-time.Second)).Add(time.Second)
+	gapEnd := wallClock(transition)
+	tick := s.cron.Next(gapStart.Add(-time.Nanosecond))
+	if tick.IsZero() || !tick.Before(gapEnd) {
+		return time.Time{}, false
+	}
+
+	return transition, true
+}
+
+// NewCronSchedule parses standard cron spec or spec with seconds field.
+// By default, spec is evaluated in the local time zone.
+func NewCronSchedule(spec string, options ...CronOption) (Schedule, error) {
+	s := &CronSchedule{
+		spec:     spec,
+		location: time.Local,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	expr := strings.TrimSpace(spec)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		i := strings.IndexByte(expr, ' ')
+		if i < 0 {
+			return nil, fmt.Errorf("cron %q: missing spec after time zone", spec)
+		}
+
+		name := expr[strings.IndexByte(expr, '=')+1 : i]
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", spec, err)
+		}
+
+		s.location = location
+		expr = strings.TrimSpace(expr[i:])
+	}
+
+	var err error
+	if len(strings.Fields(expr)) == 6 {
+		s.cron, err = cron.Parse(expr)
+	} else {
+		s.cron, err = cron.ParseStandard(expr)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return &CronSchedule{
-		cron: s,
-		spec: spec,
-	}, nil
+
+	return s, nil
+}
+
+type CronOption func(*CronSchedule)
+
+// WithLocation sets time zone, where the cron spec is evaluated.
+func WithLocation(location *time.Location) CronOption {
+	return func(s *CronSchedule) {
+		s.location = location
+	}
+}
+
+// JitterSchedule shifts each tick of the schedule by the random duration within the window,
+// so the replicas and jobs with the same schedule don't fire simultaneously.
+// Ticks of the underlying schedule are calculated from the ticks without jitter, so jitter is not accumulated.
+// Replicas pick different ticks, so the lease of the Locker (see WithLocker) doesn't keep them on the same tick:
+// the replica, whose tick is after expiration of the lease, executes the tick again.
+// Keep the window shorter than the lease TTL, when the schedule is used with the Locker.
+type JitterSchedule struct {
+	mx       sync.Mutex
+	schedule Schedule
+	window   time.Duration
+	base     time.Time
+	tick     time.Time
+}
+
+func (s *JitterSchedule) NextTick(now time.Time) time.Time {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if !s.tick.IsZero() && now.Equal(s.tick) {
+		now = s.base
+	}
+
+	s.base = s.schedule.NextTick(now)
+	if s.base.IsZero() {
+		s.tick = time.Time{}
+		return s.tick
+	}
+
+	s.tick = s.base.Add(time.Duration(rand.Int63n(int64(s.window))))
+	return s.tick
+}
+
+func (s *JitterSchedule) String() string {
+	return fmt.Sprintf("%s with jitter %s", s.schedule.String(), s.window.String())
+}
+
+func NewJitterSchedule(schedule Schedule, window time.Duration) Schedule {
+	if window <= 0 {
+		return schedule
+	}
+
+	return &JitterSchedule{
+		schedule: schedule,
+		window:   window,
+	}
+}
+
+// BlackoutSchedule skips ticks of the schedule, that are excluded by the calendar.
+type BlackoutSchedule struct {
+	schedule Schedule
+	calendar Calendar
+}
+
+func (s *BlackoutSchedule) NextTick(now time.Time) time.Time {
+	tick := s.schedule.NextTick(now)
+	for i := 0; i < maxBlackoutTicks && !tick.IsZero(); i++ {
+		if !s.calendar.Excludes(tick) {
+			return tick
+		}
+		tick = s.schedule.NextTick(tick)
+	}
+
+	return time.Time{}
+}
+
+func (s *BlackoutSchedule) String() string {
+	return fmt.Sprintf("%s with blackouts", s.schedule.String())
+}
+
+// NewBlackoutSchedule creates schedule, that skips ticks excluded by the calendar.
+// Schedule without allowed ticks among the nearest 100000 ones is considered completed.
+func NewBlackoutSchedule(schedule Schedule, calendar Calendar) Schedule {
+	return &BlackoutSchedule{
+		schedule: schedule,
+		calendar: calendar,
+	}
+}
+
+const (
+	cronStarBit      = 1 << 63
+	maxBlackoutTicks = 100000
+)
+
+// isRepeatedWallClock returns true if the same wall clock time already occurred
+// before the backward transition of the time zone.
+func isRepeatedWallClock(t time.Time) bool {
+	_, offset := t.Zone()
+	_, earlier := t.Add(-2 * time.Hour).Zone()
+	if earlier <= offset {
+		return false
+	}
+
+	prev := t.Add(-time.Duration(earlier-offset) * time.Second)
+	return wallClock(prev).Equal(wallClock(t))
+}
+
+// wallClock returns wall clock time of t in UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
 }
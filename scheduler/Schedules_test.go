@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCronSchedule(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	type Test struct {
+		spec     string
+		location *time.Location
+		now      time.Time
+		expected []time.Time
+	}
+
+	tests := map[string]Test{
+		"location": {
+			spec:     "0 9 * * *",
+			location: newYork,
+			now:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		"time zone prefix": {
+			spec:     "CRON_TZ=America/New_York 0 9 * * *",
+			location: time.UTC,
+			now:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		"seconds": {
+			spec:     "*/15 * * * * *",
+			location: time.UTC,
+			now:      time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 0, 0, 15, 0, time.UTC),
+				time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC),
+			},
+		},
+		"spring forward": {
+			spec:     "30 2 * * *",
+			location: newYork,
+			now:      time.Date(2024, 3, 9, 12, 0, 0, 0, newYork),
+			expected: []time.Time{
+				time.Date(2024, 3, 10, 3, 0, 0, 0, newYork),
+				time.Date(2024, 3, 11, 2, 30, 0, 0, newYork),
+			},
+		},
+		"fall back": {
+			spec:     "30 1 * * *",
+			location: newYork,
+			now:      time.Date(2024, 11, 2, 12, 0, 0, 0, newYork),
+			expected: []time.Time{
+				time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 4, 1, 30, 0, 0, newYork),
+			},
+		},
+		"fall back hourly": {
+			spec:     "0 * * * *",
+			location: newYork,
+			now:      time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC),
+				time.Date(2024, 11, 3, 7, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			s, err := NewCronSchedule(test.spec, WithLocation(test.location))
+			require.NoError(t, err)
+
+			now := test.now
+			for _, expected := range test.expected {
+				now = s.NextTick(now)
+				assert.True(t, expected.Equal(now), "expected %s, actual %s", expected, now)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Invalid(t *testing.T) {
+	_, err := NewCronSchedule("CRON_TZ=Unknown/Zone 0 9 * * *")
+	assert.Error(t, err)
+	_, err = NewCronSchedule("0 9 * *")
+	assert.Error(t, err)
+}
+
+func TestJitterSchedule(t *testing.T) {
+	window := time.Minute
+	s := NewJitterSchedule(NewPeriodicSchedule(time.Hour), window)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tick := start
+	for i := 1; i <= 10; i++ {
+		tick = s.NextTick(tick)
+		base := start.Add(time.Duration(i) * time.Hour)
+		assert.False(t, tick.Before(base))
+		assert.True(t, tick.Before(base.Add(window)))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.False(t, s.NextTick(start).IsZero())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBlackoutSchedule(t *testing.T) {
+	holidays := NewHolidayCalendar(time.UTC, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	maintenance := NewWindowCalendar(Window{
+		From: time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 1, 4, 9, 30, 0, 0, time.UTC),
+	})
+	cron, err := NewCronSchedule("0 9 * * *", WithLocation(time.UTC))
+	require.NoError(t, err)
+	s := NewBlackoutSchedule(cron, Calendars{holidays, maintenance})
+
+	tick := s.NextTick(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC), tick)
+
+	all := NewBlackoutSchedule(cron, NewWindowCalendar(Window{To: time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)}))
+	assert.True(t, all.NextTick(time.Now()).IsZero())
+}
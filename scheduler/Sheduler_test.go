@@ -19,8 +19,9 @@ func TestNewPeriodicSchedule(t *testing.T) {
 func TestScheduler(t *testing.T) {
 	doneCh := make(chan struct{})
 	ticks := make(map[string]int)
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 
-	s := NewScheduler(doneCh, nil)
+	s := NewScheduler(doneCh, nil, WithClock(clock))
 	require.NotNil(t, s)
 	s.Register(NewAction(
 		"class1",
@@ -50,9 +51,14 @@ func TestScheduler(t *testing.T) {
 	))
 	s.Start(context.Background())
 
-	time.Sleep(100 * time.Millisecond)
+	// ticks are aligned to the schedule, so the ticks due at 100ms are fired too
+	for i := 0; i < 20; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Millisecond)
+	}
+	clock.BlockUntil(1)
 	require.Equal(t, map[string]int{
-		"class1": 9,
+		"class1": 10,
 		"class2": 3,
 		"class3": 2,
 	}, ticks)
@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JobState is a persistent state of the event.
+type JobState struct {
+	Name    string    `json:"name"`
+	LastRun time.Time `json:"last_run"`
+	NextRun time.Time `json:"next_run"`
+}
+
+// JobStore keeps states of the events between restarts.
+type JobStore interface {
+	// Load returns state of the event or nil, if state is absent.
+	Load(ctx context.Context, name string) (*JobState, error)
+	Save(ctx context.Context, state *JobState) error
+}
+
+type MemoryJobStore struct {
+	mx     sync.Mutex
+	states map[string]JobState
+}
+
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		states: make(map[string]JobState),
+	}
+}
+
+func (that *MemoryJobStore) Load(ctx context.Context, name string) (*JobState, error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	state, ok := that.states[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &state, nil
+}
+
+func (that *MemoryJobStore) Save(ctx context.Context, state *JobState) error {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	that.states[state.Name] = *state
+	return nil
+}
+
+// FileJobStore keeps states of the events in the json file.
+type FileJobStore struct {
+	mx       sync.Mutex
+	fileName string
+	states   map[string]JobState
+}
+
+func NewFileJobStore(fileName string) *FileJobStore {
+	return &FileJobStore{
+		fileName: fileName,
+	}
+}
+
+func (that *FileJobStore) Load(ctx context.Context, name string) (*JobState, error) {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if err := that.open(); err != nil {
+		return nil, err
+	}
+
+	state, ok := that.states[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &state, nil
+}
+
+func (that *FileJobStore) Save(ctx context.Context, state *JobState) error {
+	that.mx.Lock()
+	defer that.mx.Unlock()
+
+	if err := that.open(); err != nil {
+		return err
+	}
+
+	that.states[state.Name] = *state
+	return that.flush()
+}
+
+func (that *FileJobStore) open() error {
+	if that.states != nil {
+		return nil
+	}
+
+	states := make(map[string]JobState)
+	data, err := os.ReadFile(that.fileName)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &states); err != nil {
+			return err
+		}
+	}
+
+	that.states = states
+	return nil
+}
+
+// flush writes states into the temporary file and replaces the original one with it.
+func (that *FileJobStore) flush() error {
+	data, err := json.MarshalIndent(that.states, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(that.fileName), 0755); err != nil {
+		return err
+	}
+
+	tmp := that.fileName + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, that.fileName)
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileJobStore(t *testing.T) {
+	ctx := context.Background()
+	fileName := filepath.Join(t.TempDir(), "jobs.json")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	store := NewFileJobStore(fileName)
+	state, err := store.Load(ctx, "job")
+	require.NoError(t, err)
+	require.Nil(t, state)
+
+	expected := &JobState{Name: "job", LastRun: now, NextRun: now.Add(time.Hour)}
+	require.NoError(t, store.Save(ctx, expected))
+
+	state, err = NewFileJobStore(fileName).Load(ctx, "job")
+	require.NoError(t, err)
+	assert.Equal(t, expected, state)
+}
+
+func TestScheduler_MisfirePolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy   MisfirePolicy
+		expected int64
+	}{
+		"skip":     {policy: MisfireSkip, expected: 0},
+		"run once": {policy: MisfireRunOnce, expected: 1},
+		"run all":  {policy: MisfireRunAll, expected: 3},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			doneCh := make(chan struct{})
+			defer close(doneCh)
+
+			now := time.Now()
+			store := NewMemoryJobStore()
+			require.NoError(t, store.Save(ctx, &JobState{
+				Name:    "job",
+				LastRun: now.Add(-170 * time.Millisecond),
+				NextRun: now.Add(-120 * time.Millisecond),
+			}))
+
+			var ticks int64
+			s := NewScheduler(doneCh, nil, WithJobStore(store))
+			s.Register(
+				NewAction(
+					"job",
+					NewPeriodicSchedule(50*time.Millisecond),
+					func(ctx context.Context) error {
+						atomic.AddInt64(&ticks, 1)
+						return nil
+					},
+				),
+				WithMisfirePolicy(test.policy),
+			)
+			s.Start(ctx)
+
+			time.Sleep(15 * time.Millisecond)
+			assert.Equal(t, test.expected, atomic.LoadInt64(&ticks))
+		})
+	}
+}
+
+type blockingJobStore struct {
+	JobStore
+	loading chan struct{}
+	release chan struct{}
+}
+
+func (that *blockingJobStore) Load(ctx context.Context, name string) (*JobState, error) {
+	that.loading <- struct{}{}
+	<-that.release
+	return that.JobStore.Load(ctx, name)
+}
+
+func TestScheduler_StoreWithoutLock(t *testing.T) {
+	ctx := context.Background()
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	store := &blockingJobStore{
+		JobStore: NewMemoryJobStore(),
+		loading:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	s := NewScheduler(doneCh, nil, WithJobStore(store))
+	s.Start(ctx)
+
+	registered := make(chan error)
+	go func() {
+		registered <- s.Register(NewAction("job", NewPeriodicSchedule(time.Hour), func(ctx context.Context) error {
+			return nil
+		}))
+	}()
+
+	<-store.loading
+	assert.Empty(t, s.Jobs())
+	close(store.release)
+	require.NoError(t, <-registered)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	state, err := store.JobStore.Load(ctx, "job")
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, jobs[0].NextTick, state.NextRun)
+}
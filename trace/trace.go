@@ -0,0 +1,234 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderTraceparent = "traceparent"
+	HeaderTracestate  = "tracestate"
+)
+
+const (
+	FlagsSampled byte = 0x01
+)
+
+const (
+	supportedVersion  = 0
+	invalidVersion    = 0xff
+	traceparentLength = 55
+)
+
+var (
+	ErrInvalidTraceparent = errors.New("invalid traceparent")
+)
+
+// TraceID identifies the whole trace.
+type TraceID [16]byte
+
+func (that TraceID) IsValid() bool {
+	return that != TraceID{}
+}
+
+func (that TraceID) String() string {
+	return hex.EncodeToString(that[:])
+}
+
+// SpanID identifies the single operation of the trace.
+type SpanID [8]byte
+
+func (that SpanID) IsValid() bool {
+	return that != SpanID{}
+}
+
+func (that SpanID) String() string {
+	return hex.EncodeToString(that[:])
+}
+
+// SpanContext is W3C trace context of the operation.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+}
+
+func (that SpanContext) IsValid() bool {
+	return that.TraceID.IsValid() && that.SpanID.IsValid()
+}
+
+func (that SpanContext) IsSampled() bool {
+	return that.Flags&FlagsSampled != 0
+}
+
+// Traceparent returns value of the traceparent header.
+func (that SpanContext) Traceparent() string {
+	return fmt.Sprintf("%02x-%s-%s-%02x", supportedVersion, that.TraceID, that.SpanID, that.Flags)
+}
+
+// NewChild returns context of the child operation of the same trace.
+func (that SpanContext) NewChild() SpanContext {
+	child := that
+	child.SpanID = NewSpanID()
+	return child
+}
+
+// New returns context of the new sampled trace.
+func New() SpanContext {
+	return SpanContext{
+		TraceID: NewTraceID(),
+		SpanID:  NewSpanID(),
+		Flags:   FlagsSampled,
+	}
+}
+
+func NewTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func NewSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// ParseTraceparent parses value of the traceparent header.
+// Values of the future versions are accepted, if they start with the known fields.
+func ParseTraceparent(s string) (SpanContext, error) {
+	var sc SpanContext
+
+	s = strings.TrimSpace(s)
+	if len(s) < traceparentLength {
+		return sc, ErrInvalidTraceparent
+	}
+
+	version, ok := parseByte(s[0:2])
+	if !ok || version == invalidVersion {
+		return sc, ErrInvalidTraceparent
+	}
+	if version == supportedVersion && len(s) != traceparentLength {
+		return sc, ErrInvalidTraceparent
+	}
+	if len(s) > traceparentLength && s[traceparentLength] != '-' {
+		return sc, ErrInvalidTraceparent
+	}
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return sc, ErrInvalidTraceparent
+	}
+
+	if !decodeHex(sc.TraceID[:], s[3:35]) || !decodeHex(sc.SpanID[:], s[36:52]) {
+		return sc, ErrInvalidTraceparent
+	}
+	if sc.Flags, ok = parseByte(s[53:55]); !ok {
+		return sc, ErrInvalidTraceparent
+	}
+	if !sc.IsValid() {
+		return sc, ErrInvalidTraceparent
+	}
+
+	return sc, nil
+}
+
+// parseByte parses two lowercase hex digits.
+func parseByte(s string) (byte, bool) {
+	var b [1]byte
+	if !decodeHex(b[:], s) {
+		return 0, false
+	}
+	return b[0], true
+}
+
+// decodeHex decodes lowercase hex digits, as the specification requires.
+func decodeHex(dst []byte, s string) bool {
+	if strings.ToLower(s) != s {
+		return false
+	}
+	n, err := hex.Decode(dst, []byte(s))
+	return err == nil && n == len(dst)
+}
+
+type contextKeyType int
+
+const contextKey contextKeyType = 0
+
+// NewContext returns new context with the span context.
+func NewContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey, sc)
+}
+
+// FromContext returns span context of the context.
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(contextKey).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// Ensure returns context with the span context, starting the new trace if there is none.
+func Ensure(ctx context.Context) context.Context {
+	if _, ok := FromContext(ctx); ok {
+		return ctx
+	}
+	return NewContext(ctx, New())
+}
+
+// Carrier transfers trace context through the headers of the transport.
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+type MapCarrier map[string]string
+
+func (that MapCarrier) Get(key string) string {
+	return that[key]
+}
+
+func (that MapCarrier) Set(key, value string) {
+	that[key] = value
+}
+
+type HeaderCarrier http.Header
+
+func (that HeaderCarrier) Get(key string) string {
+	return http.Header(that).Get(key)
+}
+
+func (that HeaderCarrier) Set(key, value string) {
+	http.Header(that).Set(key, value)
+}
+
+// Inject writes span context of the context into the carrier.
+func Inject(ctx context.Context, carrier Carrier) {
+	sc, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	carrier.Set(HeaderTraceparent, sc.Traceparent())
+	if sc.TraceState != "" {
+		carrier.Set(HeaderTracestate, sc.TraceState)
+	}
+}
+
+// Extract returns context with span context read from the carrier.
+// The context is returned unchanged, if the carrier has no valid trace context.
+func Extract(ctx context.Context, carrier Carrier) context.Context {
+	sc, err := ParseTraceparent(carrier.Get(HeaderTraceparent))
+	if err != nil {
+		return ctx
+	}
+
+	sc.TraceState = carrier.Get(HeaderTracestate)
+	return NewContext(ctx, sc)
+}
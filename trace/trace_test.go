@@ -0,0 +1,87 @@
+package trace
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	type Test struct {
+		src   string
+		valid bool
+	}
+
+	tests := map[string]Test{
+		"valid": {
+			src:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			valid: true,
+		},
+		"future version": {
+			src:   "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			valid: true,
+		},
+		"invalid version": {
+			src: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		"extra data of version 00": {
+			src: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+		"zero trace id": {
+			src: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		"zero span id": {
+			src: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		},
+		"uppercase": {
+			src: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		},
+		"short": {
+			src: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sc, err := ParseTraceparent(test.src)
+			if !test.valid {
+				assert.ErrorIs(t, err, ErrInvalidTraceparent)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+			assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+			assert.True(t, sc.IsSampled())
+		})
+	}
+}
+
+func TestSpanContext_Traceparent(t *testing.T) {
+	sc := New()
+	parsed, err := ParseTraceparent(sc.Traceparent())
+	require.NoError(t, err)
+	assert.Equal(t, sc, parsed)
+
+	child := sc.NewChild()
+	assert.Equal(t, sc.TraceID, child.TraceID)
+	assert.NotEqual(t, sc.SpanID, child.SpanID)
+}
+
+func TestInjectExtract(t *testing.T) {
+	sc := New()
+	sc.TraceState = "vendor=value"
+	ctx := NewContext(context.Background(), sc)
+
+	header := http.Header{}
+	Inject(ctx, HeaderCarrier(header))
+	assert.Equal(t, sc.Traceparent(), header.Get("Traceparent"))
+
+	extracted, ok := FromContext(Extract(context.Background(), HeaderCarrier(header)))
+	require.True(t, ok)
+	assert.Equal(t, sc, extracted)
+
+	_, ok = FromContext(Extract(context.Background(), MapCarrier{HeaderTraceparent: "broken"}))
+	assert.False(t, ok)
+}